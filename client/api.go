@@ -0,0 +1,38 @@
+package client
+
+import "context"
+
+// API lists the operations the provider performs against Timescale. It is
+// implemented by *Client, and by fake.Client to run the provider offline.
+type API interface {
+	// ProjectID returns the project the operations are sent to.
+	ProjectID() string
+	// ForProject returns an API sending the operations to another project.
+	ForProject(projectID string) API
+
+	// Services
+	CreateService(ctx context.Context, request CreateServiceRequest) (*CreateServiceResponse, error)
+	RenameService(ctx context.Context, serviceID string, newName string) error
+	SetReplicaCount(ctx context.Context, serviceID string, replicaCount int) error
+	ResizeInstance(ctx context.Context, serviceID string, config ResourceConfig) error
+	GetService(ctx context.Context, id string) (*Service, error)
+	GetRecoveryWindow(ctx context.Context, id string) (*RecoveryWindow, error)
+	GetAllServices(ctx context.Context) ([]*Service, error)
+	DeleteService(ctx context.Context, id string) (*Service, error)
+	ToggleConnectionPooler(ctx context.Context, serviceID string, enable bool) error
+
+	// VPCs
+	GetVPCs(ctx context.Context) ([]*VPC, error)
+	GetVPCByName(ctx context.Context, name string) (*VPC, error)
+	GetVPCByID(ctx context.Context, vpcID int64) (*VPC, error)
+	AttachServiceToVPC(ctx context.Context, serviceID string, vpcID int64) error
+	DetachServiceFromVPC(ctx context.Context, serviceID string, vpcID int64) error
+	CreateVPC(ctx context.Context, name, cidr, regionCode string) (*VPC, error)
+	RenameVPC(ctx context.Context, vpcID int64, newName string) error
+	DeleteVPC(ctx context.Context, vpcID int64) error
+
+	// Products
+	GetProducts(ctx context.Context) ([]*Product, error)
+}
+
+var _ API = &Client{}
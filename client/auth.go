@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	jwtFromCCOperation = "GetJWTForClientCredentials"
+
+	// tokenRefreshWindow is how long before its expiry a token is refreshed.
+	tokenRefreshWindow = 2 * time.Minute
+	// credentialsRefreshWindow is how long before their expiry short-lived
+	// credentials are fetched again from their source.
+	credentialsRefreshWindow = 30 * time.Second
+)
+
+// Credentials are client credentials. Expiration is set when they are
+// short-lived.
+type Credentials struct {
+	AccessKey  string
+	SecretKey  string
+	Expiration time.Time
+}
+
+// CredentialsSource returns client credentials, e.g. from an external
+// command. It is called again when the credentials it returned expire.
+type CredentialsSource func(ctx context.Context) (Credentials, error)
+
+// WithCredentialsSource makes the client fetch its client credentials from
+// source whenever it needs to exchange them for a token.
+func WithCredentialsSource(source CredentialsSource) Option {
+	return func(c *Client) {
+		c.credentialsSource = source
+	}
+}
+
+// Authenticate exchanges the client credentials of the client, set with
+// WithClientCredentials or WithCredentialsSource, for a token used by the
+// following requests. Requests exchange them on demand otherwise, so calling
+// Authenticate is only needed to check the credentials up front.
+func (c *Client) Authenticate(ctx context.Context) error {
+	if !c.hasCredentials() {
+		return errors.New("the client has no client credentials")
+	}
+	return c.authenticate(ctx)
+}
+
+// authenticate exchanges the client credentials for a token.
+func (c *Client) authenticate(ctx context.Context) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	return c.exchangeCredentials(ctx)
+}
+
+// JWTFromCC exchanges the client credentials for a JWT used by all the
+// following requests. The credentials are kept to refresh the token when it
+// expires.
+func JWTFromCC(ctx context.Context, c *Client, accessKey, secretKey string) error {
+	c.mu.Lock()
+	c.accessKey = accessKey
+	c.secretKey = secretKey
+	c.mu.Unlock()
+	return c.authenticate(ctx)
+}
+
+// JWTFromSource exchanges the credentials of the client's credentials
+// source for a JWT used by all the following requests.
+func JWTFromSource(ctx context.Context, c *Client) error {
+	if c.credentialsSource == nil {
+		return errors.New("the client has no credentials source")
+	}
+	return c.authenticate(ctx)
+}
+
+// exchangeCredentials fetches a new token. The caller must hold refreshMu.
+func (c *Client) exchangeCredentials(ctx context.Context) error {
+	tflog.Trace(ctx, "Client.exchangeCredentials")
+	if err := c.loadCredentials(ctx); err != nil {
+		return err
+	}
+	c.mu.RLock()
+	variables := getJWTForClientCredentialsVariables{
+		AccessKey: c.accessKey,
+		SecretKey: c.secretKey,
+	}
+	c.mu.RUnlock()
+	data, err := c.getJWTForClientCredentials(ctx, variables)
+	if err != nil {
+		return err
+	}
+	c.setToken(data.GetJWTForClientCredentials)
+	return nil
+}
+
+// loadCredentials fetches credentials from the credentials source, if any,
+// when there are none yet or they are about to expire. The caller must hold
+// refreshMu.
+func (c *Client) loadCredentials(ctx context.Context) error {
+	if c.credentialsSource == nil {
+		return nil
+	}
+	c.mu.RLock()
+	valid := c.accessKey != "" && (c.credentialsExpiry.IsZero() || time.Until(c.credentialsExpiry) > credentialsRefreshWindow)
+	c.mu.RUnlock()
+	if valid {
+		return nil
+	}
+	tflog.Debug(ctx, "Fetching client credentials from the credentials source")
+	creds, err := c.credentialsSource(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get client credentials: %w", err)
+	}
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		return errors.New("unable to get client credentials: the access key or the secret key is empty")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accessKey = creds.AccessKey
+	c.secretKey = creds.SecretKey
+	c.credentialsExpiry = creds.Expiration
+	return nil
+}
+
+func (c *Client) hasCredentials() bool {
+	if c.credentialsSource != nil {
+		return true
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.accessKey != "" && c.secretKey != ""
+}
+
+func (c *Client) currentToken() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+
+func (c *Client) setToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	c.tokenExpiry = jwtExpiry(token)
+}
+
+// ensureToken refreshes the token when it is about to expire.
+func (c *Client) ensureToken(ctx context.Context) error {
+	if !c.tokenExpiring() {
+		return nil
+	}
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	// Another request may have refreshed the token while we were waiting.
+	if !c.tokenExpiring() {
+		return nil
+	}
+	tflog.Debug(ctx, "Refreshing the API token before it expires")
+	return c.exchangeCredentials(ctx)
+}
+
+func (c *Client) tokenExpiring() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.token == "" {
+		return true
+	}
+	// Tokens without a readable expiry are only refreshed when rejected.
+	return !c.tokenExpiry.IsZero() && time.Until(c.tokenExpiry) < tokenRefreshWindow
+}
+
+// refreshToken replaces a token the API rejected. Concurrent callers that
+// were rejected with the same token share a single refresh.
+func (c *Client) refreshToken(ctx context.Context, rejected string) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	if c.currentToken() != rejected {
+		return nil
+	}
+	return c.exchangeCredentials(ctx)
+}
+
+// isUnauthorizedResponse reports whether a request was rejected because of
+// its token, either through the HTTP status or a GraphQL error.
+func isUnauthorizedResponse(data []byte, err error) bool {
+	if err != nil {
+		var statusErr *StatusError
+		return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnauthorized
+	}
+	var envelope struct {
+		Errors Errors `json:"errors"`
+	}
+	if json.Unmarshal(data, &envelope) != nil {
+		return false
+	}
+	return hasCode(envelope.Errors, CodeUnauthenticated)
+}
+
+// jwtExpiry returns the expiry found in the exp claim of a JWT, or the zero
+// time when the token has none or cannot be decoded.
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}
+	}
+	exp, err := claims.Exp.Float64()
+	if err != nil || exp <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(exp), 0)
+}
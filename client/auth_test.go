@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func makeJWT(t *testing.T, exp time.Time, id int32) string {
+	t.Helper()
+	claims, err := json.Marshal(map[string]any{"exp": exp.Unix(), "jti": id})
+	require.NoError(t, err)
+	return "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString(claims) + ".signature"
+}
+
+// authServer issues a new token for every credentials exchange and rejects
+// requests made with any other token than the last one issued.
+type authServer struct {
+	t         *testing.T
+	lifetime  time.Duration
+	exchanges atomic.Int32
+	mu        sync.Mutex
+	current   string
+}
+
+func (s *authServer) handle(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		OperationName string `json:"operationName"`
+	}
+	require.NoError(s.t, json.NewDecoder(r.Body).Decode(&req))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if req.OperationName == jwtFromCCOperation {
+		s.current = makeJWT(s.t, time.Now().Add(s.lifetime), s.exchanges.Add(1))
+		_, _ = fmt.Fprintf(w, `{"data":{"getJWTForClientCredentials":%q}}`, s.current)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+s.current {
+		_, _ = w.Write([]byte(`{"errors":[{"message":"unauthenticated","extensions":{"code":"UNAUTHENTICATED"}}]}`))
+		return
+	}
+	_, _ = w.Write([]byte(`{"data":{"products":[]}}`))
+}
+
+func (s *authServer) revoke() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current = "revoked"
+}
+
+func TestJWTExpiry(t *testing.T) {
+	exp := time.Unix(1700000000, 0)
+	require.Equal(t, exp, jwtExpiry(makeJWT(t, exp, 1)))
+	require.True(t, jwtExpiry("not-a-jwt").IsZero())
+	require.True(t, jwtExpiry("a.!!!.c").IsZero())
+}
+
+func TestAuth_RefreshesExpiringToken(t *testing.T) {
+	server := &authServer{t: t, lifetime: time.Minute}
+	c := newTestClient(t, server.handle)
+	require.NoError(t, JWTFromCC(context.Background(), c, "access", "secret"))
+	require.EqualValues(t, 1, server.exchanges.Load())
+
+	// The token expires within the refresh window, so every call refreshes it.
+	_, err := c.GetProducts(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 2, server.exchanges.Load())
+}
+
+func TestAuth_KeepsValidToken(t *testing.T) {
+	server := &authServer{t: t, lifetime: time.Hour}
+	c := newTestClient(t, server.handle)
+	require.NoError(t, JWTFromCC(context.Background(), c, "access", "secret"))
+
+	for i := 0; i < 3; i++ {
+		_, err := c.GetProducts(context.Background())
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 1, server.exchanges.Load())
+}
+
+func TestAuth_RetriesOnceOnUnauthorized(t *testing.T) {
+	server := &authServer{t: t, lifetime: time.Hour}
+	c := newTestClient(t, server.handle)
+	require.NoError(t, JWTFromCC(context.Background(), c, "access", "secret"))
+
+	server.revoke()
+	_, err := c.GetProducts(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 2, server.exchanges.Load())
+}
+
+func TestAuth_ConcurrentRefreshes(t *testing.T) {
+	server := &authServer{t: t, lifetime: time.Hour}
+	c := newTestClient(t, server.handle)
+	require.NoError(t, JWTFromCC(context.Background(), c, "access", "secret"))
+
+	server.revoke()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.GetProducts(context.Background())
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	require.EqualValues(t, 2, server.exchanges.Load())
+}
+
+func TestAuth_StaticTokenIsNotRefreshed(t *testing.T) {
+	server := &authServer{t: t, lifetime: time.Hour}
+	c := newTestClient(t, server.handle)
+
+	_, err := c.GetProducts(context.Background())
+	require.True(t, IsUnauthorized(err))
+	require.EqualValues(t, 0, server.exchanges.Load())
+}
+
+func TestAuth_ClientCredentialsOption(t *testing.T) {
+	server := &authServer{t: t, lifetime: time.Hour}
+	httpServer := httptest.NewServer(http.HandlerFunc(server.handle))
+	t.Cleanup(httpServer.Close)
+	c := New("project", WithURL(httpServer.URL), WithClientCredentials("access", "secret"))
+
+	// The credentials are exchanged by the first request.
+	_, err := c.GetProducts(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 1, server.exchanges.Load())
+
+	require.NoError(t, c.Authenticate(context.Background()))
+	require.EqualValues(t, 2, server.exchanges.Load())
+
+	err = New("project", WithURL(httpServer.URL)).Authenticate(context.Background())
+	require.ErrorContains(t, err, "no client credentials")
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long list queries are cached when no TTL is
+// configured.
+const DefaultCacheTTL = 30 * time.Second
+
+// cachedOperations lists the GraphQL operations whose responses are cached.
+// They list every service, VPC or product of a project, and are repeated by
+// each resource and data source during a single Terraform operation.
+var cachedOperations = map[string]bool{
+	"GetAllServices": true,
+	"GetAllVPCs":     true,
+	"GetProducts":    true,
+}
+
+// isMutation tells whether an operation may change a project.
+func isMutation(operationName string) bool {
+	return !idempotentOperations[operationName] && operationName != jwtFromCCOperation
+}
+
+// WithCacheTTL caches the responses of the list queries for ttl. Any
+// mutation of a project drops the responses cached for it. A ttl of zero
+// disables the cache.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = newQueryCache(ttl)
+	}
+}
+
+// queryCache holds the responses of list queries, shared by the resources
+// and data sources of a provider. Responses are stored as raw JSON, so every
+// caller decodes its own copy.
+type queryCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[cacheKey]*cacheEntry
+}
+
+type cacheKey struct {
+	projectID string
+	// request is the GraphQL request body.
+	request string
+}
+
+// cacheEntry is a cached response, or a request in flight when done is not
+// closed yet. Concurrent callers wait for the request in flight instead of
+// sending their own.
+type cacheEntry struct {
+	done    chan struct{}
+	data    []byte
+	err     error
+	expires time.Time
+}
+
+func newQueryCache(ttl time.Duration) *queryCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &queryCache{ttl: ttl, entries: map[cacheKey]*cacheEntry{}}
+}
+
+// enabled tells whether responses are cached.
+func (q *queryCache) enabled() bool {
+	return q != nil
+}
+
+// get returns the cached response for the request, or the one returned by
+// fetch. hit tells whether fetch was avoided.
+func (q *queryCache) get(projectID string, request []byte, fetch func() ([]byte, error)) (data []byte, hit bool, err error) {
+	key := cacheKey{projectID: projectID, request: string(request)}
+	q.mu.Lock()
+	if entry, ok := q.entries[key]; ok {
+		select {
+		case <-entry.done:
+			if time.Now().Before(entry.expires) {
+				q.mu.Unlock()
+				return entry.data, true, nil
+			}
+		default:
+			q.mu.Unlock()
+			<-entry.done
+			return entry.data, true, entry.err
+		}
+	}
+	entry := &cacheEntry{done: make(chan struct{})}
+	q.entries[key] = entry
+	q.mu.Unlock()
+
+	entry.data, entry.err = fetch()
+	entry.expires = time.Now().Add(q.ttl)
+	q.mu.Lock()
+	if entry.err != nil || len(responseErrors(entry.data)) > 0 {
+		// Failures are not cached, but are shared with the callers waiting
+		// for them.
+		if q.entries[key] == entry {
+			delete(q.entries, key)
+		}
+	}
+	close(entry.done)
+	q.mu.Unlock()
+	return entry.data, false, entry.err
+}
+
+// invalidate drops the responses cached for a project.
+func (q *queryCache) invalidate(projectID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for key := range q.entries {
+		if key.projectID == projectID {
+			delete(q.entries, key)
+		}
+	}
+}
+
+// responseErrors returns the errors carried by a GraphQL response.
+func responseErrors(data []byte) Errors {
+	var envelope struct {
+		Errors Errors `json:"errors"`
+	}
+	if json.Unmarshal(data, &envelope) != nil {
+		return nil
+	}
+	return envelope.Errors
+}
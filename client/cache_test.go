@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingHandler answers the list queries with empty lists and counts the
+// requests of each operation.
+type countingHandler struct {
+	mu       sync.Mutex
+	requests map[string]int
+	// release, when set, holds the responses until it is closed.
+	release chan struct{}
+	// failures is the number of list queries answered with an error.
+	failures atomic.Int32
+}
+
+func (h *countingHandler) handle(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		OperationName string `json:"operationName"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	h.mu.Lock()
+	if h.requests == nil {
+		h.requests = map[string]int{}
+	}
+	h.requests[req.OperationName]++
+	h.mu.Unlock()
+	if h.release != nil {
+		<-h.release
+	}
+	if h.failures.Add(-1) >= 0 {
+		_, _ = w.Write([]byte(`{"errors":[{"message":"try again","extensions":{"code":"INTERNAL"}}]}`))
+		return
+	}
+	switch req.OperationName {
+	case "GetAllServices":
+		_, _ = w.Write([]byte(`{"data":{"getAllServices":[{"id":"svc","name":"ingest"}]}}`))
+	case "GetAllVPCs":
+		_, _ = w.Write([]byte(`{"data":{"getAllVpcs":[{"id":"1","name":"vpc"}]}}`))
+	case "GetVPCByName":
+		// A VPC missing from the list, e.g. created since it was listed.
+		_, _ = w.Write([]byte(`{"data":{"getVpcByName":{"id":"2","name":"new"}}}`))
+	default:
+		_, _ = w.Write([]byte(`{"data":{"renameService":true}}`))
+	}
+}
+
+func (h *countingHandler) count(operationName string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.requests[operationName]
+}
+
+func TestCache_ListQueries(t *testing.T) {
+	h := &countingHandler{}
+	c := newTestClient(t, h.handle, WithCacheTTL(time.Minute))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		services, err := c.GetAllServices(ctx)
+		require.NoError(t, err)
+		require.Len(t, services, 1)
+		// Callers get their own copy of the response.
+		services[0].Name = "changed"
+	}
+	require.Equal(t, 1, h.count("GetAllServices"))
+	services, err := c.GetAllServices(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "ingest", services[0].Name)
+
+	// VPCs are looked up by name in the cached list.
+	_, err = c.GetVPCByName(ctx, "vpc")
+	require.NoError(t, err)
+	require.Equal(t, 1, h.count("GetAllVPCs"))
+	require.Equal(t, 0, h.count("GetVPCByName"))
+	// VPCs missing from the cached list are asked to the API, and the list
+	// is dropped since it is stale.
+	vpc, err := c.GetVPCByName(ctx, "new")
+	require.NoError(t, err)
+	require.Equal(t, "2", vpc.ID)
+	require.Equal(t, 1, h.count("GetVPCByName"))
+	_, err = c.GetVPCs(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, h.count("GetAllVPCs"))
+
+	// Mutations of the project clear the cache.
+	require.NoError(t, c.RenameService(ctx, "svc", "ingest-2"))
+	_, err = c.GetAllServices(ctx)
+	require.NoError(t, err)
+	_, err = c.GetVPCs(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, h.count("GetAllServices"))
+	require.Equal(t, 3, h.count("GetAllVPCs"))
+}
+
+func TestCache_Expires(t *testing.T) {
+	h := &countingHandler{}
+	c := newTestClient(t, h.handle, WithCacheTTL(time.Millisecond))
+	for i := 0; i < 2; i++ {
+		_, err := c.GetAllServices(context.Background())
+		require.NoError(t, err)
+		time.Sleep(5 * time.Millisecond)
+	}
+	require.Equal(t, 2, h.count("GetAllServices"))
+}
+
+func TestCache_Disabled(t *testing.T) {
+	h := &countingHandler{}
+	c := newTestClient(t, h.handle, WithCacheTTL(0))
+	for i := 0; i < 2; i++ {
+		_, err := c.GetAllServices(context.Background())
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, h.count("GetAllServices"))
+}
+
+func TestCache_ErrorsAreNotCached(t *testing.T) {
+	h := &countingHandler{}
+	h.failures.Store(1)
+	c := newTestClient(t, h.handle, WithCacheTTL(time.Minute))
+	_, err := c.GetAllServices(context.Background())
+	require.ErrorContains(t, err, "try again")
+	_, err = c.GetAllServices(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, h.count("GetAllServices"))
+}
+
+func TestCache_SharesRequestsInFlight(t *testing.T) {
+	h := &countingHandler{release: make(chan struct{})}
+	c := newTestClient(t, h.handle, WithCacheTTL(time.Minute))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			services, err := c.GetAllServices(context.Background())
+			require.NoError(t, err)
+			require.Len(t, services, 1)
+		}()
+	}
+	require.Eventually(t, func() bool { return h.count("GetAllServices") == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(h.release)
+	wg.Wait()
+	require.Equal(t, 1, h.count("GetAllServices"))
+}
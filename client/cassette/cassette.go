@@ -0,0 +1,277 @@
+// Package cassette records the GraphQL traffic of a client to a fixture file
+// and replays it without network access.
+//
+// Secrets are redacted before anything is written, and replayed requests are
+// matched on their operation name and redacted variables, so a cassette
+// recorded with real credentials can be replayed with any credentials. The
+// names the client generates for services and VPCs are ignored when
+// matching, since they differ between recording and replay.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces secrets in recorded requests and responses.
+const Redacted = "REDACTED"
+
+// ProjectID replaces project IDs in recorded requests and responses, so the
+// project used to record does not have to be used to replay.
+const ProjectID = "PROJECT_ID"
+
+// redactedFields are the request variables and response fields holding
+// secrets.
+var redactedFields = map[string]string{
+	"accessKey":                  Redacted,
+	"secretKey":                  Redacted,
+	"token":                      Redacted,
+	"password":                   Redacted,
+	"initialPassword":            Redacted,
+	"getJWTForClientCredentials": Redacted,
+	"projectId":                  ProjectID,
+	"projectID":                  ProjectID,
+}
+
+// generatedName matches the names the client generates for services and VPCs
+// created without one.
+var generatedName = regexp.MustCompile(`\b(db|vpc)-\d{5}\b`)
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	OperationName string          `json:"operationName"`
+	Variables     json.RawMessage `json:"variables,omitempty"`
+	StatusCode    int             `json:"statusCode"`
+	Response      json.RawMessage `json:"response,omitempty"`
+	// Body holds responses that are not JSON, e.g. gateway errors.
+	Body string `json:"body,omitempty"`
+}
+
+// Cassette is an http.RoundTripper that records or replays interactions.
+type Cassette struct {
+	path      string
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	// replayed tracks which interactions were served already.
+	replayed map[*Interaction]bool
+}
+
+// NewRecorder returns a cassette sending requests through transport and
+// recording them. Save writes them to path.
+func NewRecorder(path string, transport http.RoundTripper) *Cassette {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Cassette{path: path, transport: transport}
+}
+
+// Load returns a cassette replaying the interactions recorded at path.
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cassette{path: path, replayed: map[*Interaction]bool{}}
+	if err := json.Unmarshal(data, &c.interactions); err != nil {
+		return nil, fmt.Errorf("decoding cassette %s: %w", path, err)
+	}
+	// Variables are compared byte for byte, undo the indentation of Save.
+	for _, interaction := range c.interactions {
+		if len(interaction.Variables) == 0 {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, interaction.Variables); err != nil {
+			return nil, fmt.Errorf("decoding cassette %s: %w", path, err)
+		}
+		interaction.Variables = buf.Bytes()
+	}
+	return c, nil
+}
+
+// Save writes the recorded interactions to the cassette file.
+func (c *Cassette) Save() error {
+	if c.transport == nil {
+		return errors.New("cannot save a replayed cassette")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0o600)
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+	}
+	operationName, variables, projectID, err := parseRequest(body)
+	if err != nil {
+		return nil, err
+	}
+	if c.transport == nil {
+		return c.replay(req, operationName, variables)
+	}
+	return c.record(req, body, operationName, variables, projectID)
+}
+
+func (c *Cassette) record(req *http.Request, body []byte, operationName string, variables json.RawMessage, projectID string) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+	resp, err := c.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	interaction := &Interaction{
+		OperationName: operationName,
+		Variables:     variables,
+		StatusCode:    resp.StatusCode,
+	}
+	if json.Valid(data) {
+		if interaction.Response, err = redact(data, projectID); err != nil {
+			return nil, err
+		}
+	} else {
+		interaction.Body = string(data)
+	}
+	c.mu.Lock()
+	c.interactions = append(c.interactions, interaction)
+	c.mu.Unlock()
+	return resp, nil
+}
+
+// replay serves the first interaction matching the request that was not
+// served yet. Once all matching interactions were served, the last one is
+// served again: the number of polls for a status is not deterministic.
+func (c *Cassette) replay(req *http.Request, operationName string, variables json.RawMessage) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var match *Interaction
+	for _, interaction := range c.interactions {
+		if interaction.OperationName != operationName || !bytes.Equal(normalize(interaction.Variables), normalize(variables)) {
+			continue
+		}
+		match = interaction
+		if !c.replayed[interaction] {
+			break
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("cassette %s has no interaction for %s with variables %s", c.path, operationName, variables)
+	}
+	c.replayed[match] = true
+
+	body := []byte(match.Body)
+	if match.Response != nil {
+		body = match.Response
+	}
+	header := http.Header{}
+	if match.Response != nil {
+		header.Set("Content-Type", "application/json")
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", match.StatusCode, http.StatusText(match.StatusCode)),
+		StatusCode:    match.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// normalize replaces the generated names in variables, so that requests
+// differing only by them match.
+func normalize(variables json.RawMessage) []byte {
+	return generatedName.ReplaceAll(variables, []byte("$1-NNNNN"))
+}
+
+// parseRequest returns the operation name, the redacted variables and the
+// project ID of a GraphQL request. The variables are re-encoded with sorted
+// keys so they can be compared byte for byte.
+func parseRequest(body []byte) (string, json.RawMessage, string, error) {
+	var req struct {
+		OperationName string          `json:"operationName"`
+		Variables     json.RawMessage `json:"variables"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return "", nil, "", fmt.Errorf("decoding GraphQL request: %w", err)
+	}
+	if len(req.Variables) == 0 {
+		return req.OperationName, nil, "", nil
+	}
+	var project struct {
+		ProjectID string `json:"projectId"`
+	}
+	_ = json.Unmarshal(req.Variables, &project)
+	variables, err := redact(req.Variables, project.ProjectID)
+	return req.OperationName, variables, project.ProjectID, err
+}
+
+// redact replaces the secrets of a JSON document, and the project ID where
+// it is embedded in other values such as hostnames.
+func redact(data []byte, projectID string) (json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactValue(value, projectID))
+}
+
+func redactValue(value any, projectID string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if replacement, ok := redactedFields[key]; ok {
+				if _, isString := field.(string); isString {
+					v[key] = replacement
+					continue
+				}
+			}
+			v[key] = redactValue(field, projectID)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item, projectID)
+		}
+	case string:
+		if projectID != "" {
+			return strings.ReplaceAll(v, projectID, ProjectID)
+		}
+	}
+	return value
+}
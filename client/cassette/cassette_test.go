@@ -0,0 +1,127 @@
+package cassette
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
+	"github.com/timescale/terraform-provider-timescale/internal/testserver"
+)
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	s, server := testserver.Start(testserver.Options{
+		AccessKey:       "test-access-key",
+		SecretKey:       "test-secret-key",
+		TransitionDelay: 20 * time.Millisecond,
+	})
+	t.Cleanup(server.Close)
+
+	// Record a service creation against the test server.
+	recorder := NewRecorder(path, nil)
+	c := tsClient.New("real-project", tsClient.WithURL(server.URL), tsClient.WithTransport(recorder))
+	require.NoError(t, tsClient.JWTFromCC(context.Background(), c, "test-access-key", "test-secret-key"))
+	created, err := c.CreateService(context.Background(), tsClient.CreateServiceRequest{Name: "service", RegionCode: "us-east-1"})
+	require.NoError(t, err)
+	var recorded []string
+	require.Eventually(t, func() bool {
+		service, err := c.GetService(context.Background(), created.Service.ID)
+		require.NoError(t, err)
+		recorded = append(recorded, service.Status)
+		return service.Status == "READY"
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, recorder.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	for _, secret := range []string{"test-secret-key", "real-project", created.InitialPassword, "eyJ"} {
+		require.NotContains(t, string(data), secret)
+	}
+
+	// Replay it with other credentials and without the server.
+	server.Close()
+	requests := s.Requests("GetService")
+	player, err := Load(path)
+	require.NoError(t, err)
+	c = tsClient.New("other-project", tsClient.WithURL(server.URL),
+		tsClient.WithTransport(player),
+		tsClient.WithRetryPolicy(tsClient.RetryPolicy{MaxAttempts: 1}))
+	require.NoError(t, tsClient.JWTFromCC(context.Background(), c, "other-access", "other-secret"))
+	replayed, err := c.CreateService(context.Background(), tsClient.CreateServiceRequest{Name: "service", RegionCode: "us-east-1"})
+	require.NoError(t, err)
+	require.Equal(t, created.Service.ID, replayed.Service.ID)
+	require.Equal(t, Redacted, replayed.InitialPassword)
+	for _, status := range recorded {
+		service, err := c.GetService(context.Background(), created.Service.ID)
+		require.NoError(t, err)
+		require.Equal(t, status, service.Status)
+		require.Equal(t, ProjectID, service.ProjectID)
+	}
+	// Further polls get the last recorded response.
+	service, err := c.GetService(context.Background(), created.Service.ID)
+	require.NoError(t, err)
+	require.Equal(t, "READY", service.Status)
+	require.Equal(t, requests, s.Requests("GetService"))
+
+	_, err = c.GetService(context.Background(), "unknown")
+	require.ErrorContains(t, err, "no interaction for GetService")
+}
+
+func TestCassette_RecordsNonJSONResponses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	s, server := testserver.Start(testserver.Options{})
+	t.Cleanup(server.Close)
+	s.InjectFault("GetProducts", testserver.Fault{StatusCode: http.StatusServiceUnavailable})
+
+	recorder := NewRecorder(path, nil)
+	c := tsClient.New("project", tsClient.WithURL(server.URL), tsClient.WithToken("token"),
+		tsClient.WithTransport(recorder),
+		tsClient.WithRetryPolicy(tsClient.RetryPolicy{MaxAttempts: 2, MaxWait: time.Millisecond}))
+	_, err := c.GetProducts(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, recorder.Save())
+
+	player, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, player.interactions, 2)
+	require.Equal(t, http.StatusServiceUnavailable, player.interactions[0].StatusCode)
+	require.NotEmpty(t, player.interactions[0].Body)
+
+	c = tsClient.New("project", tsClient.WithURL(server.URL), tsClient.WithToken("token"),
+		tsClient.WithTransport(player),
+		tsClient.WithRetryPolicy(tsClient.RetryPolicy{MaxAttempts: 2, MaxWait: time.Millisecond}))
+	_, err = c.GetProducts(context.Background())
+	require.NoError(t, err)
+}
+
+func TestCassette_IgnoresGeneratedNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	_, server := testserver.Start(testserver.Options{})
+	t.Cleanup(server.Close)
+
+	recorder := NewRecorder(path, nil)
+	c := tsClient.New("project", tsClient.WithURL(server.URL), tsClient.WithToken("token"), tsClient.WithTransport(recorder))
+	created, err := c.CreateService(context.Background(), tsClient.CreateServiceRequest{RegionCode: "us-east-1"})
+	require.NoError(t, err)
+	require.Regexp(t, `^db-\d{5}$`, created.Service.Name)
+	require.NoError(t, recorder.Save())
+
+	// The replayed creation generates another name.
+	player, err := Load(path)
+	require.NoError(t, err)
+	c = tsClient.New("project", tsClient.WithURL(server.URL), tsClient.WithToken("token"),
+		tsClient.WithTransport(player),
+		tsClient.WithRetryPolicy(tsClient.RetryPolicy{MaxAttempts: 1}))
+	replayed, err := c.CreateService(context.Background(), tsClient.CreateServiceRequest{RegionCode: "us-east-1"})
+	require.NoError(t, err)
+	require.Equal(t, created.Service.ID, replayed.Service.ID)
+
+	_, err = c.CreateService(context.Background(), tsClient.CreateServiceRequest{Name: "named", RegionCode: "us-east-1"})
+	require.ErrorContains(t, err, "no interaction for CreateService")
+}
@@ -0,0 +1,382 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Client sends the operations of a Timescale project to the GraphQL API. It
+// is safe for concurrent use.
+type Client struct {
+	*session
+	projectID string
+}
+
+// session is the state shared by the clients of all the projects reached
+// with the same options, see Client.Project.
+type session struct {
+	httpClient *http.Client
+	// mu guards the token and the credentials used to refresh it, which can
+	// change while other requests are in flight.
+	mu          sync.RWMutex
+	token       string
+	tokenExpiry time.Time
+	accessKey   string
+	secretKey   string
+	// credentialsExpiry is the expiry of credentials from credentialsSource.
+	credentialsExpiry time.Time
+	// refreshMu serializes token refreshes.
+	refreshMu sync.Mutex
+	// credentialsSource, when set, provides the credentials exchanged for
+	// tokens.
+	credentialsSource CredentialsSource
+
+	url string
+	// authURL receives the client credentials exchange when it is not
+	// served by url.
+	authURL string
+	// userAgent identifies the program using the client to the API.
+	userAgent   string
+	retryPolicy RetryPolicy
+	// logBodies enables the debug logs of request and response bodies.
+	logBodies bool
+	// cache holds the responses of list queries, nil when disabled.
+	cache *queryCache
+	// limiter paces the requests of the client.
+	limiter *limiter
+	// subscriptions enables SubscribeService, see WithSubscriptions.
+	subscriptions bool
+	// subscriptionsUnsupported is set once the API refused a subscription,
+	// so that later watches poll right away.
+	subscriptionsUnsupported atomic.Bool
+}
+
+// Option configures optional behaviour of a Client.
+type Option func(*Client)
+
+// WithURL sets the URL of the GraphQL API, instead of DefaultURL.
+func WithURL(url string) Option {
+	return func(c *Client) {
+		if url != "" {
+			c.url = url
+		}
+	}
+}
+
+// WithToken makes the client authenticate its requests with an API token,
+// e.g. a JWT obtained beforehand.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+		c.tokenExpiry = jwtExpiry(token)
+	}
+}
+
+// WithClientCredentials makes the client exchange client credentials for a
+// token, before its first request and whenever the token expires. Call
+// Authenticate to exchange them right away.
+func WithClientCredentials(accessKey, secretKey string) Option {
+	return func(c *Client) {
+		c.accessKey = accessKey
+		c.secretKey = secretKey
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests, which identifies
+// the program using the client.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithAuthURL sets the URL receiving the client credentials exchange, when
+// it differs from the API URL.
+func WithAuthURL(url string) Option {
+	return func(c *Client) {
+		c.authURL = url
+	}
+}
+
+// WithTransport makes the client send its requests through the given
+// transport, e.g. to record or replay them in tests.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = transport
+	}
+}
+
+// Response is the envelope of the GraphQL responses.
+type Response[T any] struct {
+	Data   *T     `json:"data"`
+	Errors Errors `json:"errors"`
+}
+
+// execute sends an operation and returns its data, or the errors reported by
+// the API. It backs the functions of operations_gen.go.
+func execute[T any](ctx context.Context, c *Client, operationName, document string, variables any) (*T, error) {
+	req := map[string]interface{}{
+		"operationName": operationName,
+		"query":         document,
+	}
+	if variables != nil {
+		req["variables"] = variables
+	}
+	var resp Response[T]
+	if err := c.do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		for _, err := range resp.Errors {
+			err.withoutData = resp.Data == nil
+		}
+		return nil, resp.Errors
+	}
+	if resp.Data == nil {
+		return nil, errors.New("no response found")
+	}
+	return resp.Data, nil
+}
+
+// DefaultUserAgent is the User-Agent of the requests of clients created
+// without WithUserAgent.
+const DefaultUserAgent = "timescale-go-client"
+
+// New returns a client for the project with the given ID. The client sends
+// its requests to DefaultURL, unless WithURL sets another URL, and
+// authenticates them with WithToken, WithClientCredentials or
+// WithCredentialsSource.
+func New(projectID string, opts ...Option) *Client {
+	client := &Client{
+		session: &session{
+			httpClient: &http.Client{
+				Timeout: DefaultTimeout,
+			},
+			url:         DefaultURL,
+			userAgent:   DefaultUserAgent,
+			retryPolicy: DefaultRetryPolicy(),
+			limiter:     newLimiter(DefaultRateLimit()),
+		},
+		projectID: projectID,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client
+}
+
+// ProjectID returns the ID of the project of the client.
+func (c *Client) ProjectID() string {
+	return c.projectID
+}
+
+// Project returns a client for another project. It shares the connection,
+// credentials, token, cache and rate limit of c.
+func (c *Client) Project(projectID string) *Client {
+	return &Client{session: c.session, projectID: projectID}
+}
+
+// ForProject returns the client for another project as an API, see Project.
+func (c *Client) ForProject(projectID string) API {
+	return c.Project(projectID)
+}
+
+// DefaultURL is the GraphQL API of the production console.
+const DefaultURL = "https://console.cloud.timescale.com/api/query"
+
+// endpoint returns the URL an operation is sent to.
+func (c *Client) endpoint(operationName string) string {
+	if operationName == jwtFromCCOperation && c.authURL != "" {
+		return c.authURL
+	}
+	return c.url
+}
+
+func (c *Client) do(ctx context.Context, req map[string]interface{}, resp interface{}) (err error) {
+	tflog.Trace(ctx, "Client.do")
+	jsonValue, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	operationName, _ := req["operationName"].(string)
+	requestID := newRequestID()
+	ctx = tflog.SetField(ctx, "operation_name", operationName)
+	ctx = tflog.SetField(ctx, "project_id", c.projectID)
+	ctx = tflog.SetField(ctx, "request_id", requestID)
+	ctx = tflog.MaskAllFieldValuesRegexes(ctx, jwtPattern)
+	ctx = tflog.MaskMessageRegexes(ctx, jwtPattern)
+	ctx, span := tracer().Start(ctx, "graphql "+operationName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			operationNameKey.String(operationName),
+			projectIDKey.String(c.projectID),
+			requestIDKey.String(requestID),
+		))
+	var data []byte
+	defer func() { endSpan(span, data, err) }()
+
+	switch {
+	case c.cache.enabled() && cachedOperations[operationName]:
+		var hit bool
+		data, hit, err = c.cache.get(c.projectID, jsonValue, func() ([]byte, error) {
+			return c.roundTrip(ctx, operationName, requestID, jsonValue)
+		})
+		if hit {
+			tflog.Debug(ctx, "Using cached GraphQL response")
+		}
+		span.SetAttributes(cacheHitKey.Bool(hit))
+	case c.cache.enabled() && isMutation(operationName):
+		// The mutation may have been applied even if it failed.
+		defer c.cache.invalidate(c.projectID)
+		data, err = c.roundTrip(ctx, operationName, requestID, jsonValue)
+	default:
+		data, err = c.roundTrip(ctx, operationName, requestID, jsonValue)
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, resp)
+}
+
+// roundTrip sends a request and returns the response data. Requests made
+// with client credentials refresh the token before it expires, and once more
+// if the API rejects it anyway.
+func (c *Client) roundTrip(ctx context.Context, operationName, requestID string, body []byte) ([]byte, error) {
+	refreshable := operationName != jwtFromCCOperation && c.hasCredentials()
+	if refreshable {
+		if err := c.ensureToken(ctx); err != nil {
+			return nil, err
+		}
+	}
+	token := c.currentToken()
+	data, err := c.doWithRetries(ctx, operationName, requestID, body)
+	if refreshable && isUnauthorizedResponse(data, err) {
+		tflog.Debug(ctx, operationName+" was rejected as unauthorized, refreshing the token")
+		if err := c.refreshToken(ctx, token); err != nil {
+			return nil, err
+		}
+		data, err = c.doWithRetries(ctx, operationName, requestID, body)
+	}
+	return data, err
+}
+
+// doWithRetries sends the request body, retrying according to the retry
+// policy of the client.
+func (c *Client) doWithRetries(ctx context.Context, operationName, requestID string, body []byte) ([]byte, error) {
+	idempotent := idempotentOperations[operationName]
+	url := c.endpoint(operationName)
+	span := trace.SpanFromContext(ctx)
+	for attempt := 1; ; attempt++ {
+		ctx := tflog.SetField(ctx, "attempt", attempt)
+		span.SetAttributes(attemptsKey.Int(attempt))
+		data, err := c.send(ctx, url, requestID, body)
+		if err == nil {
+			return data, nil
+		}
+		if attempt >= c.retryPolicy.MaxAttempts || !shouldRetry(ctx, err, idempotent) {
+			tflog.Error(ctx, "GraphQL request failed", map[string]interface{}{"error": err.Error()})
+			return nil, err
+		}
+
+		var retryAfter time.Duration
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			retryAfter = statusErr.RetryAfter
+		}
+		wait := c.retryPolicy.backoff(attempt, retryAfter)
+		tflog.Warn(ctx, fmt.Sprintf("%s failed on attempt %d/%d with error %s, retrying in %s",
+			operationName, attempt, c.retryPolicy.MaxAttempts, err, wait), map[string]interface{}{
+			"error":      err.Error(),
+			"retry_wait": wait.String(),
+		})
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// send performs a single POST of the given body to url and returns the
+// response body. Retryable statuses and error statuses that do not carry a
+// GraphQL response are returned as a *StatusError.
+func (c *Client) send(ctx context.Context, url, requestID string, body []byte) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	c.setRequestHeaders(request)
+	request.Header.Set(requestIDHeader, requestID)
+	injectTraceContext(ctx, request.Header)
+	if c.logBodies {
+		tflog.Debug(ctx, "Sending GraphQL request", map[string]interface{}{
+			"http_request_headers": maskHeaders(request.Header),
+			"http_request_body":    maskBody(body),
+		})
+	}
+
+	release, err := c.limiter.wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	start := time.Now()
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		tflog.Debug(ctx, "GraphQL request could not be sent", map[string]interface{}{
+			"latency_ms": time.Since(start).Milliseconds(),
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+	defer response.Body.Close()
+	c.limiter.observe(ctx, response, time.Now(), c.retryPolicy.MaxWait)
+	data, err := io.ReadAll(response.Body)
+	fields := map[string]interface{}{
+		"http_status": response.StatusCode,
+		"latency_ms":  time.Since(start).Milliseconds(),
+	}
+	if id := response.Header.Get(requestIDHeader); id != "" && id != requestID {
+		fields["server_request_id"] = id
+	}
+	if c.logBodies {
+		fields["http_response_headers"] = maskHeaders(response.Header)
+		fields["http_response_body"] = maskBody(data)
+	}
+	tflog.Debug(ctx, "Received GraphQL response", fields)
+	trace.SpanFromContext(ctx).SetAttributes(httpStatusKey.Int(response.StatusCode))
+	if err != nil {
+		return nil, err
+	}
+	if isRetryableStatus(response.StatusCode) || (response.StatusCode >= http.StatusBadRequest && !json.Valid(data)) {
+		return nil, &StatusError{
+			StatusCode: response.StatusCode,
+			RetryAfter: parseRetryAfter(response.Header.Get("Retry-After"), time.Now()),
+			Body:       string(data),
+		}
+	}
+	return data, nil
+}
+
+func (c *Client) setRequestHeaders(request *http.Request) {
+	if token := c.currentToken(); token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", c.userAgent)
+}
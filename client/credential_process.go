@@ -0,0 +1,63 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// credentialProcessTimeout bounds the run of a credential process.
+const credentialProcessTimeout = time.Minute
+
+// credentialProcessOutput is what a credential process prints on stdout.
+type credentialProcessOutput struct {
+	AccessKey  string     `json:"access_key"`
+	SecretKey  string     `json:"secret_key"`
+	Expiration *time.Time `json:"expiration"`
+}
+
+// CredentialProcess returns a credentials source running command through
+// the shell. The command prints the credentials as JSON on stdout, with an
+// optional RFC 3339 expiration:
+//
+//	{"access_key": "...", "secret_key": "...", "expiration": "2024-01-01T12:00:00Z"}
+func CredentialProcess(command string) CredentialsSource {
+	return func(ctx context.Context) (Credentials, error) {
+		ctx, cancel := context.WithTimeout(ctx, credentialProcessTimeout)
+		defer cancel()
+
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+		} else {
+			cmd = exec.CommandContext(ctx, "sh", "-c", command)
+		}
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return Credentials{}, fmt.Errorf("credential process failed: %w: %s", err, msg)
+			}
+			return Credentials{}, fmt.Errorf("credential process failed: %w", err)
+		}
+
+		var out credentialProcessOutput
+		if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+			return Credentials{}, fmt.Errorf("credential process printed invalid JSON: %w", err)
+		}
+		creds := Credentials{AccessKey: out.AccessKey, SecretKey: out.SecretKey}
+		if out.Expiration != nil {
+			if time.Until(*out.Expiration) <= 0 {
+				return Credentials{}, fmt.Errorf("credential process returned credentials that expired at %s", out.Expiration.Format(time.RFC3339))
+			}
+			creds.Expiration = *out.Expiration
+		}
+		return creds, nil
+	}
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands need a POSIX shell")
+	}
+	expiration := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	creds, err := CredentialProcess(fmt.Sprintf(`echo '{"access_key":"access","secret_key":"secret","expiration":%q}'`,
+		expiration.Format(time.RFC3339)))(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, Credentials{AccessKey: "access", SecretKey: "secret", Expiration: expiration}, creds)
+
+	creds, err = CredentialProcess(`echo '{"access_key":"access","secret_key":"secret"}'`)(context.Background())
+	require.NoError(t, err)
+	require.True(t, creds.Expiration.IsZero())
+
+	_, err = CredentialProcess(`echo 'vault is sealed' >&2; exit 2`)(context.Background())
+	require.ErrorContains(t, err, "vault is sealed")
+	_, err = CredentialProcess(`echo 'not json'`)(context.Background())
+	require.ErrorContains(t, err, "invalid JSON")
+	_, err = CredentialProcess(`echo '{"access_key":"a","secret_key":"s","expiration":"2000-01-01T00:00:00Z"}'`)(context.Background())
+	require.ErrorContains(t, err, "expired")
+}
+
+func TestAuth_CredentialsSource(t *testing.T) {
+	tests := map[string]struct {
+		lifetime time.Duration
+		fetches  int
+	}{
+		"long-lived credentials are fetched once":         {lifetime: time.Hour, fetches: 1},
+		"expiring credentials are fetched on every token": {lifetime: 10 * time.Second, fetches: 3},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Tokens expire within the refresh window, so every call refreshes
+			// them.
+			server := &authServer{t: t, lifetime: time.Minute}
+			fetches := 0
+			source := func(context.Context) (Credentials, error) {
+				fetches++
+				return Credentials{AccessKey: "access", SecretKey: "secret", Expiration: time.Now().Add(test.lifetime)}, nil
+			}
+			c := newTestClient(t, server.handle, WithCredentialsSource(source))
+			require.NoError(t, JWTFromSource(context.Background(), c))
+			for i := 0; i < 2; i++ {
+				_, err := c.GetProducts(context.Background())
+				require.NoError(t, err)
+			}
+			require.EqualValues(t, 3, server.exchanges.Load())
+			require.Equal(t, test.fetches, fetches)
+		})
+	}
+}
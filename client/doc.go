@@ -0,0 +1,25 @@
+// Package client is a Go client of the Timescale GraphQL API, which manages
+// the services and VPCs of a Timescale project. It is the client of the
+// Timescale Terraform provider, and can be used by any Go program.
+//
+// A Client is created for a project with New and options, and authenticates
+// with client credentials, which it exchanges for tokens and refreshes as
+// needed:
+//
+//	c := client.New(projectID, client.WithClientCredentials(accessKey, secretKey))
+//	if err := c.Authenticate(ctx); err != nil {
+//		return err
+//	}
+//	service, err := c.GetService(ctx, serviceID)
+//
+// Every operation takes a context, which cancels it and carries the span of
+// the caller when tracing is enabled. Read operations are retried on
+// transient failures and throttling, see WithRetryPolicy and WithRateLimit.
+// Errors reported by the API are *Error values, and can be checked with
+// IsNotFound, IsUnauthorized and the other Is functions.
+//
+// Lists are returned whole by the API. ListServices and ListVPCs cut them in
+// pages, which can be walked with a Pager or collected with All.
+//
+// The fake package provides an in-memory implementation of API for tests.
+package client
@@ -0,0 +1,192 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error codes reported by the API in the extensions.code field of a GraphQL
+// error.
+const (
+	CodeNotFound        = "NOT_FOUND"
+	CodeUnauthenticated = "UNAUTHENTICATED"
+	CodeForbidden       = "FORBIDDEN"
+	CodeConflict        = "CONFLICT"
+	CodeAlreadyExists   = "ALREADY_EXISTS"
+	CodeRateLimited     = "RATE_LIMITED"
+	CodeTooManyRequests = "TOO_MANY_REQUESTS"
+	// CodeValidationFailed is reported for operations that do not match the
+	// schema of the API.
+	CodeValidationFailed = "GRAPHQL_VALIDATION_FAILED"
+)
+
+// Error is a single entry of the errors list of a GraphQL response.
+type Error struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Locations  []Location     `json:"locations,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+
+	// withoutData is set when the response carrying the error had no data.
+	withoutData bool
+}
+
+// Location points to the part of the query an error relates to.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Code returns the machine readable error code found in the extensions, or
+// an empty string when the API did not provide any.
+func (e *Error) Code() string {
+	if code, ok := e.Extensions["code"].(string); ok {
+		return code
+	}
+	return ""
+}
+
+// PathString returns the path of the field that caused the error, e.g.
+// "getService.resources.0".
+func (e *Error) PathString() string {
+	parts := make([]string, 0, len(e.Path))
+	for _, p := range e.Path {
+		parts = append(parts, fmt.Sprint(p))
+	}
+	return strings.Join(parts, ".")
+}
+
+// Errors aggregates all the errors returned in a GraphQL response.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	switch len(e) {
+	case 0:
+		return "unknown error"
+	case 1:
+		return e[0].Error()
+	}
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("%d errors occurred: %s", len(e), strings.Join(messages, "; "))
+}
+
+// Unwrap exposes every error so that errors.Is and errors.As inspect all of
+// them.
+func (e Errors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func newNotFoundError(format string, a ...any) *Error {
+	return &Error{
+		Message:    fmt.Sprintf(format, a...),
+		Extensions: map[string]any{"code": CodeNotFound},
+	}
+}
+
+// IsNotFound reports whether err means the requested object does not exist.
+// A NOT_FOUND error on a nested field of a partial response, e.g.
+// getService.resources.0, does not mean the object is gone: it only counts
+// when it is on the root field, or when the response has no data.
+func IsNotFound(err error) bool {
+	var found bool
+	walk(err, func(e error) {
+		gqlErr, ok := e.(*Error) //nolint:errorlint // walk visits wrapped errors
+		if ok && gqlErr != nil && strings.EqualFold(gqlErr.Code(), CodeNotFound) && (len(gqlErr.Path) <= 1 || gqlErr.withoutData) {
+			found = true
+		}
+	})
+	return found || hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether err means the credentials are missing,
+// invalid, expired or lack the required permissions.
+func IsUnauthorized(err error) bool {
+	return hasCode(err, CodeUnauthenticated, CodeForbidden) ||
+		hasStatus(err, http.StatusUnauthorized, http.StatusForbidden)
+}
+
+// IsConflict reports whether err means the request conflicts with the
+// current state of an object, e.g. a name that is already taken.
+func IsConflict(err error) bool {
+	return hasCode(err, CodeConflict, CodeAlreadyExists) || hasStatus(err, http.StatusConflict)
+}
+
+// IsRateLimited reports whether err means the API throttled the request.
+func IsRateLimited(err error) bool {
+	return hasCode(err, CodeRateLimited, CodeTooManyRequests) || hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsUnsupported reports whether err means the API does not know an
+// operation or one of its fields, e.g. a query the API does not serve yet.
+func IsUnsupported(err error) bool {
+	if hasCode(err, CodeValidationFailed) {
+		return true
+	}
+	var found bool
+	walk(err, func(e error) {
+		gqlErr, ok := e.(*Error) //nolint:errorlint // walk visits wrapped errors
+		if ok && gqlErr != nil && strings.HasPrefix(gqlErr.Message, "Cannot query field") {
+			found = true
+		}
+	})
+	return found
+}
+
+func hasCode(err error, codes ...string) bool {
+	var found bool
+	walk(err, func(e error) {
+		gqlErr, ok := e.(*Error) //nolint:errorlint // walk visits wrapped errors
+		if !ok || gqlErr == nil {
+			return
+		}
+		for _, code := range codes {
+			if strings.EqualFold(gqlErr.Code(), code) {
+				found = true
+			}
+		}
+	})
+	return found
+}
+
+func hasStatus(err error, statuses ...int) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	for _, status := range statuses {
+		if statusErr.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
+
+// walk calls fn for err and every error it wraps, including all the branches
+// of joined errors. errors.As alone would stop at the first *Error found.
+func walk(err error, fn func(error)) {
+	if err == nil {
+		return
+	}
+	fn(err)
+	switch e := err.(type) { //nolint:errorlint // walk unwraps by hand
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			walk(inner, fn)
+		}
+	case interface{ Unwrap() error }:
+		walk(e.Unwrap(), fn)
+	}
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors_DecodeEnvelope(t *testing.T) {
+	body := `{
+		"data": null,
+		"errors": [
+			{
+				"message": "service not found",
+				"path": ["getService", "resources", 0],
+				"locations": [{"line": 2, "column": 5}],
+				"extensions": {"code": "NOT_FOUND", "serviceId": "abc"}
+			},
+			{"message": "slow down", "extensions": {"code": "RATE_LIMITED"}}
+		]
+	}`
+	var resp Response[getServiceData]
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	require.Len(t, resp.Errors, 2)
+
+	first := resp.Errors[0]
+	require.Equal(t, CodeNotFound, first.Code())
+	require.Equal(t, "getService.resources.0", first.PathString())
+	require.Equal(t, []Location{{Line: 2, Column: 5}}, first.Locations)
+	require.Equal(t, "abc", first.Extensions["serviceId"])
+
+	var err error = resp.Errors
+	require.Equal(t, "2 errors occurred: service not found; slow down", err.Error())
+	// Whether the response had data is only known to the client.
+	require.False(t, IsNotFound(err))
+	require.True(t, IsRateLimited(err))
+	require.False(t, IsUnauthorized(err))
+	require.False(t, IsConflict(err))
+}
+
+func TestErrors_SentinelChecks(t *testing.T) {
+	tests := map[string]struct {
+		err          error
+		notFound     bool
+		unauthorized bool
+		conflict     bool
+		rateLimited  bool
+		unsupported  bool
+	}{
+		"nil":             {err: nil},
+		"no code":         {err: &Error{Message: "boom"}},
+		"not found":       {err: newNotFoundError("gone"), notFound: true},
+		"unauthenticated": {err: Errors{{Extensions: map[string]any{"code": CodeUnauthenticated}}}, unauthorized: true},
+		"forbidden":       {err: &Error{Extensions: map[string]any{"code": "forbidden"}}, unauthorized: true},
+		"already exists":  {err: &Error{Extensions: map[string]any{"code": CodeAlreadyExists}}, conflict: true},
+		"wrapped":         {err: fmt.Errorf("create: %w", Errors{{Extensions: map[string]any{"code": CodeConflict}}}), conflict: true},
+		"status 401":      {err: &StatusError{StatusCode: http.StatusUnauthorized}, unauthorized: true},
+		"status 404":      {err: &StatusError{StatusCode: http.StatusNotFound}, notFound: true},
+		"status 429":      {err: &StatusError{StatusCode: http.StatusTooManyRequests}, rateLimited: true},
+		"validation":      {err: Errors{{Extensions: map[string]any{"code": CodeValidationFailed}}}, unsupported: true},
+		"unknown field":   {err: fmt.Errorf("window: %w", &Error{Message: `Cannot query field "getServiceRecoveryWindow" on type "Query".`}), unsupported: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.notFound, IsNotFound(test.err))
+			require.Equal(t, test.unauthorized, IsUnauthorized(test.err))
+			require.Equal(t, test.conflict, IsConflict(test.err))
+			require.Equal(t, test.rateLimited, IsRateLimited(test.err))
+			require.Equal(t, test.unsupported, IsUnsupported(test.err))
+		})
+	}
+}
+
+func TestErrors_ReturnedByClient(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":[{"message":"a"},{"message":"b","extensions":{"code":"CONFLICT"}}]}`))
+	})
+	err := c.RenameService(context.Background(), "svc", "name")
+	require.EqualError(t, err, "2 errors occurred: a; b")
+	require.True(t, IsConflict(err))
+}
+
+func TestErrors_MissingObjectsAreNotFound(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"getService":null,"getVPCByName":null}}`))
+	})
+	_, err := c.GetService(context.Background(), "svc")
+	require.True(t, IsNotFound(err))
+	_, err = c.GetVPCByName(context.Background(), "vpc")
+	require.True(t, IsNotFound(err))
+}
+
+func TestErrors_NestedNotFound(t *testing.T) {
+	nested := `{"message":"resource not found","path":["getService","resources",0],"extensions":{"code":"NOT_FOUND"}}`
+	tests := map[string]struct {
+		body     string
+		notFound bool
+	}{
+		"root field": {
+			body:     `{"data":{"getService":null},"errors":[{"message":"service not found","path":["getService"],"extensions":{"code":"NOT_FOUND"}}]}`,
+			notFound: true,
+		},
+		"nested field without data": {
+			body:     `{"data":null,"errors":[` + nested + `]}`,
+			notFound: true,
+		},
+		"nested field of a partial response": {
+			body: `{"data":{"getService":{"id":"svc","status":"READY","resources":[null]}},"errors":[` + nested + `]}`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(test.body))
+			})
+			_, err := c.GetService(context.Background(), "svc")
+			require.Error(t, err)
+			require.Equal(t, test.notFound, IsNotFound(err))
+		})
+	}
+}
+
+func TestErrors_UnparsableErrorStatus(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("unauthorized"))
+	})
+	_, err := c.GetProducts(context.Background())
+	require.True(t, IsUnauthorized(err))
+}
@@ -0,0 +1,56 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/timescale/terraform-provider-timescale/client"
+)
+
+func ExampleNew() {
+	ctx := context.Background()
+	c := client.New(os.Getenv("TIMESCALE_PROJECT_ID"),
+		client.WithClientCredentials(os.Getenv("TIMESCALE_ACCESS_KEY"), os.Getenv("TIMESCALE_SECRET_KEY")),
+		client.WithUserAgent("inventory-report/1.0"),
+		client.WithCacheTTL(time.Minute))
+	if err := c.Authenticate(ctx); err != nil {
+		log.Fatal(err)
+	}
+
+	service, err := c.GetService(ctx, "abc123")
+	if client.IsNotFound(err) {
+		log.Fatal("the service does not exist")
+	} else if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(service.Name, service.Status)
+}
+
+func ExamplePager() {
+	ctx := context.Background()
+	c := client.New(os.Getenv("TIMESCALE_PROJECT_ID"), client.WithToken(os.Getenv("TIMESCALE_TOKEN")))
+
+	for pager := client.NewPager(c.ListServices, client.ListOptions{PageSize: 20}); pager.More(); {
+		services, err := pager.Next(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, service := range services {
+			fmt.Println(service.ID, service.Name)
+		}
+	}
+}
+
+func ExampleAll() {
+	ctx := context.Background()
+	c := client.New(os.Getenv("TIMESCALE_PROJECT_ID"), client.WithToken(os.Getenv("TIMESCALE_TOKEN")))
+
+	vpcs, err := client.All(ctx, c.ListVPCs, client.ListOptions{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(vpcs), "VPCs")
+}
@@ -0,0 +1,661 @@
+// Package fake provides an in-memory implementation of the Timescale client,
+// used to run the provider without network access.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
+)
+
+const (
+	DefaultProjectID  = "fakeproject"
+	DefaultRegionCode = "us-east-1"
+)
+
+// DefaultTransitions are the statuses a service goes through after being
+// created or reconfigured.
+var DefaultTransitions = []string{"QUEUED", "CONFIGURING", "READY"}
+
+var _ tsClient.API = &Client{}
+
+// Client is a stateful in-memory fake of the Timescale API. Services move
+// through Transitions after every change, one status per GetService call or,
+// when TransitionDelay is set, one status per TransitionDelay.
+type Client struct {
+	// Transitions overrides DefaultTransitions. The last status is the one
+	// the service settles in.
+	Transitions []string
+	// TransitionDelay is how long a service stays in each status before
+	// moving to the next one. When zero, services move on every GetService.
+	TransitionDelay time.Duration
+	// StaleReadyDelay is how long a READY service keeps reporting its state
+	// from before a change through GetService, as the API does until it
+	// starts applying the change.
+	StaleReadyDelay time.Duration
+	// Products is returned by GetProducts.
+	Products []*tsClient.Product
+
+	mu        sync.Mutex
+	projectID string
+	services  map[string]*service
+	vpcs      map[int64]*tsClient.VPC
+	failures  map[string][]error
+	// projects are the fakes of all the projects reached with ForProject.
+	projects *projects
+}
+
+// projects are the fakes of several projects, by project ID.
+type projects struct {
+	mu   sync.Mutex
+	byID map[string]*Client
+	// nextID numbers the services and VPCs of all the projects.
+	nextID int
+}
+
+type service struct {
+	tsClient.Service
+	// pending are the statuses the service still has to go through.
+	pending []string
+	// nextTransition is when the service moves to the next pending status,
+	// if TransitionDelay is set.
+	nextTransition time.Time
+	// stale is reported by GetService instead of the service until
+	// staleUntil, see StaleReadyDelay.
+	stale      *tsClient.Service
+	staleUntil time.Time
+	// reconfigured is set by reconfigure.
+	reconfigured bool
+}
+
+// New returns an empty fake for the given project.
+func New(projectID string) *Client {
+	if projectID == "" {
+		projectID = DefaultProjectID
+	}
+	c := newProject(projectID, &projects{byID: map[string]*Client{}})
+	c.projects.byID[projectID] = c
+	return c
+}
+
+// all returns the clients of the projects.
+func (p *projects) all() []*Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	clients := make([]*Client, 0, len(p.byID))
+	for _, c := range p.byID {
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+func newProject(projectID string, projects *projects) *Client {
+	return &Client{
+		projectID: projectID,
+		services:  map[string]*service{},
+		vpcs:      map[int64]*tsClient.VPC{},
+		failures:  map[string][]error{},
+		projects:  projects,
+	}
+}
+
+func (c *Client) ProjectID() string {
+	return c.projectID
+}
+
+// ForProject returns the fake of another project, created empty on first
+// use with the Transitions, TransitionDelay, StaleReadyDelay and Products of
+// c.
+func (c *Client) ForProject(projectID string) tsClient.API {
+	return c.Project(projectID)
+}
+
+// Project is ForProject, returning the fake itself.
+func (c *Client) Project(projectID string) *Client {
+	c.projects.mu.Lock()
+	defer c.projects.mu.Unlock()
+	p, ok := c.projects.byID[projectID]
+	if !ok {
+		p = newProject(projectID, c.projects)
+		p.Transitions = c.Transitions
+		p.TransitionDelay = c.TransitionDelay
+		p.StaleReadyDelay = c.StaleReadyDelay
+		p.Products = c.Products
+		c.projects.byID[projectID] = p
+	}
+	return p
+}
+
+// FailNext makes the next call to the given method, e.g. "GetService",
+// return err. Several failures for the same method are returned in order.
+func (c *Client) FailNext(method string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures[method] = append(c.failures[method], err)
+}
+
+// Service returns the current state of a service without moving it through
+// its transitions.
+func (c *Client) Service(id string) (*tsClient.Service, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.services[id]
+	if !ok {
+		return nil, false
+	}
+	return copyService(&s.Service), true
+}
+
+// RemoveService deletes a service behind the provider's back, as if it was
+// deleted from the console.
+func (c *Client) RemoveService(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.services, id)
+}
+
+// RemoveVPC deletes a VPC behind the provider's back.
+func (c *Client) RemoveVPC(id int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.vpcs, id)
+}
+
+// failure pops the next injected failure for method. The caller must hold mu.
+func (c *Client) failure(method string) error {
+	errs := c.failures[method]
+	if len(errs) == 0 {
+		return nil
+	}
+	c.failures[method] = errs[1:]
+	return errs[0]
+}
+
+func (c *Client) transitions() []string {
+	if len(c.Transitions) > 0 {
+		return append([]string(nil), c.Transitions...)
+	}
+	return append([]string(nil), DefaultTransitions...)
+}
+
+// reconfigure starts moving a service through its transitions again.
+func (c *Client) reconfigure(s *service) {
+	s.reconfigured = true
+	s.pending = c.transitions()
+	s.Status = s.pending[0]
+	if c.TransitionDelay > 0 {
+		s.pending = s.pending[1:]
+		s.nextTransition = time.Now().Add(c.TransitionDelay)
+	}
+}
+
+// advance moves a service through its pending statuses. Without a
+// TransitionDelay, a service only moves when it is polled.
+func (c *Client) advance(s *service, polled bool) {
+	if c.TransitionDelay <= 0 {
+		if polled && len(s.pending) > 0 {
+			s.Status = s.pending[0]
+			s.pending = s.pending[1:]
+		}
+		return
+	}
+	for len(s.pending) > 0 && !time.Now().Before(s.nextTransition) {
+		s.Status = s.pending[0]
+		s.pending = s.pending[1:]
+		s.nextTransition = s.nextTransition.Add(c.TransitionDelay)
+	}
+}
+
+// inProject reports whether projectID designates the project of c.
+func (c *Client) inProject(projectID string) bool {
+	return projectID == "" || projectID == c.projectID
+}
+
+func (c *Client) newID() int {
+	c.projects.mu.Lock()
+	defer c.projects.mu.Unlock()
+	c.projects.nextID++
+	return c.projects.nextID
+}
+
+func notFound(format string, a ...any) error {
+	return &tsClient.Error{
+		Message:    fmt.Sprintf(format, a...),
+		Extensions: map[string]any{"code": tsClient.CodeNotFound},
+	}
+}
+
+// validateFork rejects the fork configurations the API rejects.
+func validateFork(fork *tsClient.ForkConfig) error {
+	switch fork.ForkStrategy {
+	case "", tsClient.ForkStrategyLastSnapshot, tsClient.ForkStrategyNow:
+		if fork.TargetTime != "" {
+			return &tsClient.Error{Message: "targetTime is only supported by the PITR fork strategy"}
+		}
+	case tsClient.ForkStrategyPITR:
+		if _, err := time.Parse(time.RFC3339, fork.TargetTime); err != nil {
+			return &tsClient.Error{Message: fmt.Sprintf("invalid targetTime %q", fork.TargetTime)}
+		}
+	default:
+		return &tsClient.Error{Message: fmt.Sprintf("invalid fork strategy %q", fork.ForkStrategy)}
+	}
+	return nil
+}
+
+// checkForkSource checks that the source of a fork exists and, with
+// ForkStrategyPITR, that it can be restored to the target time. It runs
+// before c is locked, so that projects never lock each other.
+func (c *Client) checkForkSource(fork *tsClient.ForkConfig) error {
+	p := c
+	if !c.inProject(fork.ProjectID) {
+		p = c.Project(fork.ProjectID)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s, ok := p.services[fork.ServiceID]
+	if !ok {
+		return notFound("service %s not found", fork.ServiceID)
+	}
+	if fork.ForkStrategy != tsClient.ForkStrategyPITR {
+		return nil
+	}
+	target, _ := time.Parse(time.RFC3339, fork.TargetTime)
+	if ok, err := recoveryWindow(s).Contains(target); err != nil || !ok {
+		return &tsClient.Error{Message: fmt.Sprintf("targetTime %s is outside the recovery window of service %s", fork.TargetTime, s.ID)}
+	}
+	return nil
+}
+
+// recoveryWindow returns the recovery window of s: services can be restored
+// to any time since their creation.
+func recoveryWindow(s *service) *tsClient.RecoveryWindow {
+	return &tsClient.RecoveryWindow{
+		Earliest: s.Created,
+		Latest:   time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+func (c *Client) CreateService(_ context.Context, request tsClient.CreateServiceRequest) (*tsClient.CreateServiceResponse, error) {
+	if fork := request.ForkConfig; fork != nil {
+		if err := validateFork(fork); err != nil {
+			return nil, err
+		}
+		if err := c.checkForkSource(fork); err != nil {
+			return nil, err
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("CreateService"); err != nil {
+		return nil, err
+	}
+
+	id := c.newID()
+	s := &service{}
+	s.ID = fmt.Sprintf("svc%07d", id)
+	s.ProjectID = c.projectID
+	s.Name = request.Name
+	if s.Name == "" {
+		s.Name = fmt.Sprintf("db-%d", 10000+id)
+	}
+	s.RegionCode = request.RegionCode
+	if s.RegionCode == "" {
+		s.RegionCode = DefaultRegionCode
+	}
+	s.Created = time.Now().UTC().Format(time.RFC3339)
+	s.Type = "TIMESCALEDB"
+	s.Spec = tsClient.ServiceSpec{
+		Hostname: fmt.Sprintf("%s.%s.tsdb.cloud.timescale.com", s.ID, c.projectID),
+		Username: "tsdbadmin",
+		Port:     int64(30000 + id),
+	}
+	s.Resources = []*tsClient.Resource{{
+		ID: fmt.Sprintf("res%07d", id),
+		Spec: tsClient.ResourceSpec{
+			MilliCPU:  parseInt(request.MilliCPU),
+			MemoryGB:  parseInt(request.MemoryGB),
+			StorageGB: parseInt(request.StorageGB),
+		},
+	}}
+	if parseInt(request.ReplicaCount) > 0 {
+		s.ReplicaStatus = "async"
+	}
+	setPooler(&s.Service, request.EnableConnectionPooler)
+	if request.ForkConfig != nil {
+		if _, ok := c.services[request.ForkConfig.ServiceID]; !ok && c.inProject(request.ForkConfig.ProjectID) {
+			return nil, notFound("service %s not found", request.ForkConfig.ServiceID)
+		}
+		s.ForkedFromID = &tsClient.ForkSpec{
+			ProjectID: request.ForkConfig.ProjectID,
+			ServiceID: request.ForkConfig.ServiceID,
+			IsStandby: request.ForkConfig.IsStandby,
+		}
+	}
+	if request.VpcID > 0 {
+		if err := c.attach(s, request.VpcID); err != nil {
+			return nil, err
+		}
+	}
+	c.reconfigure(s)
+	c.services[s.ID] = s
+
+	return &tsClient.CreateServiceResponse{
+		Service:         *copyService(&s.Service),
+		InitialPassword: "password-" + s.ID,
+	}, nil
+}
+
+func (c *Client) RenameService(_ context.Context, serviceID string, newName string) error {
+	return c.updateService("RenameService", serviceID, func(s *service) error {
+		s.Name = newName
+		return nil
+	})
+}
+
+func (c *Client) SetReplicaCount(_ context.Context, serviceID string, replicaCount int) error {
+	return c.updateService("SetReplicaCount", serviceID, func(s *service) error {
+		s.ReplicaStatus = ""
+		if replicaCount > 0 {
+			s.ReplicaStatus = "async"
+		}
+		c.reconfigure(s)
+		return nil
+	})
+}
+
+func (c *Client) ResizeInstance(_ context.Context, serviceID string, config tsClient.ResourceConfig) error {
+	return c.updateService("ResizeInstance", serviceID, func(s *service) error {
+		// A value of 0 leaves the setting unchanged.
+		if v := parseInt(config.MilliCPU); v > 0 {
+			s.Resources[0].Spec.MilliCPU = v
+		}
+		if v := parseInt(config.MemoryGB); v > 0 {
+			s.Resources[0].Spec.MemoryGB = v
+		}
+		c.reconfigure(s)
+		return nil
+	})
+}
+
+func (c *Client) ToggleConnectionPooler(_ context.Context, serviceID string, enable bool) error {
+	return c.updateService("ToggleConnectionPooler", serviceID, func(s *service) error {
+		setPooler(&s.Service, enable)
+		return nil
+	})
+}
+
+func (c *Client) GetService(_ context.Context, id string) (*tsClient.Service, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GetService"); err != nil {
+		return nil, err
+	}
+	s, ok := c.services[id]
+	if !ok {
+		return nil, notFound("service %s not found", id)
+	}
+	if time.Now().Before(s.staleUntil) {
+		return copyService(s.stale), nil
+	}
+	c.advance(s, true)
+	return copyService(&s.Service), nil
+}
+
+func (c *Client) GetRecoveryWindow(_ context.Context, id string) (*tsClient.RecoveryWindow, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GetRecoveryWindow"); err != nil {
+		return nil, err
+	}
+	s, ok := c.services[id]
+	if !ok {
+		return nil, notFound("service %s not found", id)
+	}
+	return recoveryWindow(s), nil
+}
+
+func (c *Client) GetAllServices(_ context.Context) ([]*tsClient.Service, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GetAllServices"); err != nil {
+		return nil, err
+	}
+	services := make([]*tsClient.Service, 0, len(c.services))
+	for _, s := range c.services {
+		c.advance(s, false)
+		services = append(services, copyService(&s.Service))
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].ID < services[j].ID })
+	return services, nil
+}
+
+func (c *Client) DeleteService(_ context.Context, id string) (*tsClient.Service, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("DeleteService"); err != nil {
+		return nil, err
+	}
+	s, ok := c.services[id]
+	if !ok {
+		return nil, notFound("service %s not found", id)
+	}
+	delete(c.services, id)
+	return copyService(&s.Service), nil
+}
+
+func (c *Client) GetVPCs(_ context.Context) ([]*tsClient.VPC, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GetVPCs"); err != nil {
+		return nil, err
+	}
+	vpcs := make([]*tsClient.VPC, 0, len(c.vpcs))
+	for _, vpc := range c.vpcs {
+		vpcs = append(vpcs, copyVPC(vpc))
+	}
+	sort.Slice(vpcs, func(i, j int) bool { return vpcs[i].ID < vpcs[j].ID })
+	return vpcs, nil
+}
+
+func (c *Client) GetVPCByName(_ context.Context, name string) (*tsClient.VPC, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GetVPCByName"); err != nil {
+		return nil, err
+	}
+	for _, vpc := range c.vpcs {
+		if vpc.Name == name {
+			return copyVPC(vpc), nil
+		}
+	}
+	return nil, notFound("vpc %s not found", name)
+}
+
+func (c *Client) GetVPCByID(_ context.Context, vpcID int64) (*tsClient.VPC, error) {
+	c.mu.Lock()
+	err := c.failure("GetVPCByID")
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	// Like the API, VPCs are found by ID in every project.
+	for _, p := range c.projects.all() {
+		p.mu.Lock()
+		vpc, ok := p.vpcs[vpcID]
+		p.mu.Unlock()
+		if ok {
+			return copyVPC(vpc), nil
+		}
+	}
+	return nil, notFound("vpc %d not found", vpcID)
+}
+
+func (c *Client) AttachServiceToVPC(_ context.Context, serviceID string, vpcID int64) error {
+	return c.updateService("AttachServiceToVPC", serviceID, func(s *service) error {
+		return c.attach(s, vpcID)
+	})
+}
+
+func (c *Client) DetachServiceFromVPC(_ context.Context, serviceID string, vpcID int64) error {
+	return c.updateService("DetachServiceFromVPC", serviceID, func(s *service) error {
+		if s.VPCEndpoint == nil || s.VPCEndpoint.VPCID != strconv.FormatInt(vpcID, 10) {
+			return fmt.Errorf("service %s is not attached to vpc %d", serviceID, vpcID)
+		}
+		s.VPCEndpoint = nil
+		s.VPCID = ""
+		return nil
+	})
+}
+
+func (c *Client) CreateVPC(_ context.Context, name, cidr, regionCode string) (*tsClient.VPC, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("CreateVPC"); err != nil {
+		return nil, err
+	}
+	id := int64(c.newID())
+	if name == "" {
+		name = fmt.Sprintf("vpc-%d", 10000+id)
+	}
+	for _, vpc := range c.vpcs {
+		if vpc.Name == name {
+			return nil, &tsClient.Error{
+				Message:    fmt.Sprintf("vpc %s already exists", name),
+				Extensions: map[string]any{"code": tsClient.CodeConflict},
+			}
+		}
+	}
+	vpc := &tsClient.VPC{
+		ID:            strconv.FormatInt(id, 10),
+		ProvisionedID: fmt.Sprintf("vpc-%08d", id),
+		ProjectID:     c.projectID,
+		CIDR:          cidr,
+		Name:          name,
+		RegionCode:    regionCode,
+		Status:        "CREATED",
+		Created:       "2024-01-01T00:00:00Z",
+		Updated:       "2024-01-01T00:00:00Z",
+	}
+	c.vpcs[id] = vpc
+	return copyVPC(vpc), nil
+}
+
+func (c *Client) RenameVPC(_ context.Context, vpcID int64, newName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("RenameVPC"); err != nil {
+		return err
+	}
+	vpc, ok := c.vpcs[vpcID]
+	if !ok {
+		return notFound("vpc %d not found", vpcID)
+	}
+	vpc.Name = newName
+	return nil
+}
+
+func (c *Client) DeleteVPC(_ context.Context, vpcID int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("DeleteVPC"); err != nil {
+		return err
+	}
+	if _, ok := c.vpcs[vpcID]; !ok {
+		return notFound("vpc %d not found", vpcID)
+	}
+	delete(c.vpcs, vpcID)
+	return nil
+}
+
+func (c *Client) GetProducts(_ context.Context) ([]*tsClient.Product, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GetProducts"); err != nil {
+		return nil, err
+	}
+	return c.Products, nil
+}
+
+// updateService applies fn to a service under lock, after checking for
+// injected failures. A READY service fn reconfigures keeps reporting its
+// previous state for StaleReadyDelay.
+func (c *Client) updateService(method, serviceID string, fn func(*service) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure(method); err != nil {
+		return err
+	}
+	s, ok := c.services[serviceID]
+	if !ok {
+		return notFound("service %s not found", serviceID)
+	}
+	before := copyService(&s.Service)
+	s.reconfigured = false
+	if err := fn(s); err != nil {
+		return err
+	}
+	if c.StaleReadyDelay > 0 && before.Status == "READY" && s.reconfigured {
+		s.stale = before
+		s.staleUntil = time.Now().Add(c.StaleReadyDelay)
+		s.nextTransition = s.nextTransition.Add(c.StaleReadyDelay)
+	}
+	return nil
+}
+
+// attach links a service to a VPC. The caller must hold mu.
+func (c *Client) attach(s *service, vpcID int64) error {
+	if _, ok := c.vpcs[vpcID]; !ok {
+		return notFound("vpc %d not found", vpcID)
+	}
+	s.VPCEndpoint = &tsClient.VPCEndpoint{
+		Host:  fmt.Sprintf("%s.vpc.%d.tsdb.cloud.timescale.com", s.ID, vpcID),
+		Port:  s.Spec.Port,
+		VPCID: strconv.FormatInt(vpcID, 10),
+	}
+	s.VPCID = s.VPCEndpoint.VPCID
+	return nil
+}
+
+func setPooler(s *tsClient.Service, enable bool) {
+	s.Spec.ConnectionPoolerEnabled = enable
+	s.Spec.PoolerHostName = ""
+	s.Spec.PoolerPort = 0
+	if enable {
+		s.Spec.PoolerHostName = "pooler-" + s.Spec.Hostname
+		s.Spec.PoolerPort = s.Spec.Port + 1
+	}
+}
+
+func parseInt(s string) int64 {
+	v, _ := strconv.ParseInt(s, 10, 64)
+	return v
+}
+
+func copyService(s *tsClient.Service) *tsClient.Service {
+	cp := *s
+	cp.Resources = make([]*tsClient.Resource, len(s.Resources))
+	for i, r := range s.Resources {
+		resource := *r
+		cp.Resources[i] = &resource
+	}
+	if s.VPCEndpoint != nil {
+		endpoint := *s.VPCEndpoint
+		cp.VPCEndpoint = &endpoint
+	}
+	if s.ForkedFromID != nil {
+		fork := *s.ForkedFromID
+		cp.ForkedFromID = &fork
+	}
+	return &cp
+}
+
+func copyVPC(v *tsClient.VPC) *tsClient.VPC {
+	cp := *v
+	cp.PeeringConnections = append([]*tsClient.PeeringConnection(nil), v.PeeringConnections...)
+	return &cp
+}
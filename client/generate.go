@@ -0,0 +1,5 @@
+package client
+
+// The types and functions of the operations in queries/ are generated from
+// schema.graphql and schema_assumptions.graphql.
+//go:generate go run ../tools/graphqlgen -schema schema.graphql -extensions schema_assumptions.graphql -operations queries -out operations_gen.go
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
+)
+
+const (
+	DefaultMaxConcurrentRequests = 8
+	DefaultRequestsPerSecond     = 10
+
+	// minRequestsPerSecond is the slowest rate the limiter slows down to.
+	minRequestsPerSecond = 0.2
+	// rateLimitPause is how long requests are paused after a 429 response
+	// that does not say when to retry.
+	rateLimitPause = time.Second
+)
+
+// RateLimit controls how fast a client sends requests. It is shared by all
+// the requests of the client, whichever resource or data source sends them.
+type RateLimit struct {
+	// MaxConcurrentRequests caps the number of requests in flight. Zero
+	// means no cap.
+	MaxConcurrentRequests int
+	// RequestsPerSecond is the rate of requests, with bursts up to
+	// MaxConcurrentRequests. Zero means no limit until the API throttles
+	// the client.
+	RequestsPerSecond float64
+}
+
+// DefaultRateLimit returns the rate limit used when none is configured.
+func DefaultRateLimit() RateLimit {
+	return RateLimit{
+		MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+		RequestsPerSecond:     DefaultRequestsPerSecond,
+	}
+}
+
+// WithRateLimit overrides the default rate limit of the client.
+func WithRateLimit(limit RateLimit) Option {
+	return func(c *Client) {
+		c.limiter = newLimiter(limit)
+	}
+}
+
+// limiter holds requests back to stay within the rate limit of the client.
+// When the API throttles the client, with a 429 response or rate limit
+// headers, it pauses every request and halves the rate, which then recovers
+// with every successful response.
+type limiter struct {
+	// slots holds a value per request in flight, nil when unlimited.
+	slots  chan struct{}
+	bucket *rate.Limiter
+	// rate is the configured rate, math.Inf(1) when unlimited.
+	rate rate.Limit
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+func newLimiter(limit RateLimit) *limiter {
+	l := &limiter{rate: rate.Inf}
+	burst := 1
+	if limit.MaxConcurrentRequests > 0 {
+		l.slots = make(chan struct{}, limit.MaxConcurrentRequests)
+		burst = limit.MaxConcurrentRequests
+	}
+	if limit.RequestsPerSecond > 0 {
+		l.rate = rate.Limit(limit.RequestsPerSecond)
+	}
+	l.bucket = rate.NewLimiter(l.rate, burst)
+	return l
+}
+
+// wait blocks until a request can be sent, and returns a function to call
+// once its response is read.
+func (l *limiter) wait(ctx context.Context) (func(), error) {
+	for {
+		l.mu.Lock()
+		pause := time.Until(l.pausedUntil)
+		l.mu.Unlock()
+		if pause <= 0 {
+			break
+		}
+		tflog.Debug(ctx, "Waiting for the API rate limit to reset", map[string]interface{}{
+			"rate_limit_wait": pause.String(),
+		})
+		timer := time.NewTimer(pause)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if err := l.bucket.Wait(ctx); err != nil {
+		return nil, err
+	}
+	if l.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// observe adapts the limiter to a response of the API. Pauses requested by
+// the API are capped by maxPause, like the waits between retries.
+func (l *limiter) observe(ctx context.Context, response *http.Response, now time.Time, maxPause time.Duration) {
+	var pause time.Duration
+	throttled := response.StatusCode == http.StatusTooManyRequests
+	if throttled {
+		pause = parseRetryAfter(response.Header.Get("Retry-After"), now)
+		if pause <= 0 {
+			pause = rateLimitPause
+		}
+	} else if remaining, ok := rateLimitRemaining(response.Header); ok && remaining == 0 {
+		throttled = true
+		pause = rateLimitReset(response.Header, now)
+	}
+
+	if !throttled {
+		// Recover a tenth of the configured rate with every success.
+		if current := l.bucket.Limit(); current < l.rate {
+			next := current + l.baseRate()/10
+			if next >= l.baseRate() {
+				next = l.rate
+			}
+			l.bucket.SetLimit(next)
+		}
+		return
+	}
+
+	pause = min(pause, maxPause)
+	slower := max(min(l.bucket.Limit(), l.baseRate())/2, minRequestsPerSecond)
+	l.bucket.SetLimit(slower)
+	l.mu.Lock()
+	if until := now.Add(pause); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	l.mu.Unlock()
+	tflog.Warn(ctx, "The API is rate limiting requests, slowing down", map[string]interface{}{
+		"http_status":         response.StatusCode,
+		"rate_limit_wait":     pause.String(),
+		"requests_per_second": float64(slower),
+	})
+}
+
+// baseRate is the rate the limiter recovers towards.
+func (l *limiter) baseRate() rate.Limit {
+	if l.rate == rate.Inf {
+		return DefaultRequestsPerSecond
+	}
+	return l.rate
+}
+
+// rateLimitRemaining reads the number of requests left in the current rate
+// limit window, from the X-RateLimit-Remaining or RateLimit-Remaining
+// header.
+func rateLimitRemaining(header http.Header) (int, bool) {
+	for _, name := range []string{"X-RateLimit-Remaining", "RateLimit-Remaining"} {
+		if value := header.Get(name); value != "" {
+			remaining, err := strconv.Atoi(value)
+			return remaining, err == nil
+		}
+	}
+	return 0, false
+}
+
+// rateLimitReset reads how long until the current rate limit window resets,
+// from the X-RateLimit-Reset or RateLimit-Reset header, given either in
+// seconds or as a Unix time.
+func rateLimitReset(header http.Header, now time.Time) time.Duration {
+	for _, name := range []string{"X-RateLimit-Reset", "RateLimit-Reset"} {
+		value, err := strconv.ParseFloat(header.Get(name), 64)
+		if err != nil || value <= 0 || math.IsInf(value, 0) {
+			continue
+		}
+		// Seconds since the epoch are far larger than any window.
+		if value > 1e9 {
+			return max(time.Unix(int64(value), 0).Sub(now), 0)
+		}
+		return time.Duration(value * float64(time.Second))
+	}
+	return rateLimitPause
+}
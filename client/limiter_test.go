@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestLimiter_MaxConcurrentRequests(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"data":{"products":[]}}`))
+	}, WithRateLimit(RateLimit{MaxConcurrentRequests: 2}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.GetProducts(context.Background())
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	require.EqualValues(t, 2, peak.Load())
+}
+
+func TestLimiter_RequestsPerSecond(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"products":[]}}`))
+	}, WithRateLimit(RateLimit{MaxConcurrentRequests: 1, RequestsPerSecond: 20}))
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		_, err := c.GetProducts(context.Background())
+		require.NoError(t, err)
+	}
+	// The first request uses the burst, the next ones wait 50ms each.
+	require.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+}
+
+func TestLimiter_ThrottlingSlowsDownAllCallers(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"products":[]}}`))
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 1, MaxWait: 100 * time.Millisecond}))
+
+	// Mutations are not retried, but the next request of any caller waits
+	// for the pause requested by the API, capped by MaxWait.
+	_, err := c.DeleteService(context.Background(), "svc")
+	require.Error(t, err)
+	start := time.Now()
+	_, err = c.GetProducts(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestLimiter_Observe(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	respond := func(status int, header map[string]string) *http.Response {
+		r := &http.Response{StatusCode: status, Header: http.Header{}}
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		return r
+	}
+
+	l := newLimiter(RateLimit{MaxConcurrentRequests: 4, RequestsPerSecond: 8})
+	l.observe(ctx, respond(http.StatusTooManyRequests, map[string]string{"Retry-After": "2"}), now, time.Minute)
+	require.Equal(t, rate.Limit(4), l.bucket.Limit())
+	require.Equal(t, now.Add(2*time.Second), l.pausedUntil)
+	l.observe(ctx, respond(http.StatusTooManyRequests, nil), now, time.Minute)
+	require.Equal(t, rate.Limit(2), l.bucket.Limit())
+	// An earlier end of pause does not shorten the current one.
+	require.Equal(t, now.Add(2*time.Second), l.pausedUntil)
+
+	// Successes bring the rate back to the configured one.
+	for i := 0; i < 10; i++ {
+		l.observe(ctx, respond(http.StatusOK, nil), now, time.Minute)
+	}
+	require.Equal(t, rate.Limit(8), l.bucket.Limit())
+
+	// An exhausted rate limit window pauses requests until it resets.
+	l.observe(ctx, respond(http.StatusOK, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "30"}), now, 10*time.Second)
+	require.Equal(t, rate.Limit(4), l.bucket.Limit())
+	require.Equal(t, now.Add(10*time.Second), l.pausedUntil)
+	l.observe(ctx, respond(http.StatusOK, map[string]string{"RateLimit-Remaining": "5", "RateLimit-Reset": "30"}), now, time.Minute)
+	require.Equal(t, now.Add(10*time.Second), l.pausedUntil)
+
+	// Unlimited clients slow down from the default rate, and recover to
+	// no limit.
+	l = newLimiter(RateLimit{})
+	l.observe(ctx, respond(http.StatusTooManyRequests, nil), now, time.Minute)
+	require.Equal(t, rate.Limit(DefaultRequestsPerSecond)/2, l.bucket.Limit())
+	for i := 0; i < 5; i++ {
+		l.observe(ctx, respond(http.StatusOK, nil), now, time.Minute)
+	}
+	require.Equal(t, rate.Inf, l.bucket.Limit())
+}
+
+func TestRateLimitReset(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := map[string]struct {
+		header map[string]string
+		want   time.Duration
+	}{
+		"seconds":      {map[string]string{"X-RateLimit-Reset": "12"}, 12 * time.Second},
+		"unix time":    {map[string]string{"X-RateLimit-Reset": "1700000030"}, 30 * time.Second},
+		"past":         {map[string]string{"X-RateLimit-Reset": "1600000000"}, 0},
+		"ietf draft":   {map[string]string{"RateLimit-Reset": "3"}, 3 * time.Second},
+		"missing":      {nil, rateLimitPause},
+		"invalid":      {map[string]string{"X-RateLimit-Reset": "soon"}, rateLimitPause},
+		"fractional s": {map[string]string{"X-RateLimit-Reset": "0.5"}, 500 * time.Millisecond},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range test.header {
+				header.Set(k, v)
+			}
+			require.Equal(t, test.want, rateLimitReset(header, now))
+		})
+	}
+}
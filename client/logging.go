@@ -0,0 +1,107 @@
+package client
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// requestIDHeader carries the ID of a request, to match the provider logs
+// with the API logs.
+const requestIDHeader = "X-Request-Id"
+
+// maskedValue replaces secrets in logs.
+const maskedValue = "***"
+
+// jwtPattern matches JSON Web Tokens wherever they appear in logs.
+var jwtPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+// secretFields are the request variables and response fields masked from
+// logged bodies.
+var secretFields = map[string]bool{
+	"accessKey":                  true,
+	"secretKey":                  true,
+	"password":                   true,
+	"initialPassword":            true,
+	"getJWTForClientCredentials": true,
+}
+
+// secretHeaders are the headers masked from logged requests.
+var secretHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// WithBodyLogging makes the client log the headers and bodies of requests
+// and responses at debug level, with secrets masked.
+func WithBodyLogging(enabled bool) Option {
+	return func(c *Client) {
+		c.logBodies = enabled
+	}
+}
+
+// newRequestID returns a random ID for a request.
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// maskBody returns a body for logging, with secrets masked.
+func maskBody(data []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return jwtPattern.ReplaceAllString(string(data), maskedValue)
+	}
+	masked, err := json.Marshal(maskValue(value))
+	if err != nil {
+		return maskedValue
+	}
+	return jwtPattern.ReplaceAllString(string(masked), maskedValue)
+}
+
+func maskValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if secretFields[key] && field != nil {
+				v[key] = maskedValue
+				continue
+			}
+			v[key] = maskValue(field)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = maskValue(item)
+		}
+	}
+	return value
+}
+
+// maskHeaders returns headers for logging, with secrets masked.
+func maskHeaders(header http.Header) string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := strings.Join(header.Values(key), ", ")
+		if secretHeaders[http.CanonicalHeaderKey(key)] {
+			value = maskedValue
+		}
+		lines = append(lines, key+": "+jwtPattern.ReplaceAllString(value, maskedValue))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskBody(t *testing.T) {
+	jwt := makeJWT(t, time.Now(), 1)
+	body := `{"variables":{"accessKey":"ak","secretKey":"sk"},"data":{"createService":{"initialPassword":"pw","service":{"id":"svc"}},"note":"token ` + jwt + `"}}`
+	masked := maskBody([]byte(body))
+	for _, secret := range []string{`"ak"`, `"sk"`, `"pw"`, jwt} {
+		require.NotContains(t, masked, secret)
+	}
+	require.Contains(t, masked, `"id":"svc"`)
+	require.Equal(t, "bearer ***", maskBody([]byte("bearer "+jwt)))
+}
+
+func TestMaskHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer token")
+	header.Set("Content-Type", "application/json")
+	require.Equal(t, "Authorization: ***\nContent-Type: application/json", maskHeaders(header))
+}
+
+func TestDo_LogsStructuredFields(t *testing.T) {
+	var requestIDs []string
+	token := makeJWT(t, time.Now().Add(time.Hour), 1)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requestIDs = append(requestIDs, r.Header.Get(requestIDHeader))
+		if len(requestIDs) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"getService":{"id":"svc","status":"READY"}},"extensions":{"initialPassword":"hunter2"}}`))
+	}, WithBodyLogging(true))
+	c.setToken(token)
+
+	var logs bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &logs)
+	_, err := c.GetService(ctx, "svc")
+	require.NoError(t, err)
+
+	output := logs.String()
+	require.NotContains(t, output, token)
+	require.NotContains(t, output, "hunter2")
+	require.Contains(t, output, "http_response_body")
+
+	entries, err := tflogtest.MultilineJSONDecode(&logs)
+	require.NoError(t, err)
+	var responses []map[string]interface{}
+	for _, entry := range entries {
+		if entry["@message"] == "Received GraphQL response" {
+			responses = append(responses, entry)
+		}
+	}
+	require.Len(t, responses, 2)
+	for i, entry := range responses {
+		require.Equal(t, "GetService", entry["operation_name"])
+		require.Equal(t, "project", entry["project_id"])
+		require.Equal(t, requestIDs[i], entry["request_id"])
+		require.EqualValues(t, i+1, entry["attempt"])
+		require.Contains(t, entry, "latency_ms")
+	}
+	require.EqualValues(t, http.StatusServiceUnavailable, responses[0]["http_status"])
+	require.EqualValues(t, http.StatusOK, responses[1]["http_status"])
+	require.NotEmpty(t, requestIDs[0])
+	require.Equal(t, requestIDs[0], requestIDs[1])
+}
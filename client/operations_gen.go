@@ -0,0 +1,931 @@
+// Code generated by graphqlgen from schema.graphql, schema_assumptions.graphql and queries/*.graphql. DO NOT EDIT.
+
+package client
+
+import "context"
+
+// AutoscaleSettings are the storage autoscaling settings of a service.
+type AutoscaleSettings struct {
+	Enabled bool `json:"enabled"`
+}
+
+// CreateServicePayload is a created service and the initial password of its tsdbadmin user.
+type CreateServicePayload struct {
+	InitialPassword string  `json:"initialPassword"`
+	Service         Service `json:"service"`
+}
+
+// ForkConfig selects the service a new service is forked from.
+type ForkConfig struct {
+	ProjectID string `json:"projectID"`
+	ServiceID string `json:"serviceID"`
+	IsStandby bool   `json:"isStandby,omitempty"`
+	// Defaults to LAST_SNAPSHOT.
+	ForkStrategy string `json:"forkStrategy,omitempty"`
+	// The time the source service is restored to with the PITR strategy, in RFC 3339 format.
+	TargetTime string `json:"targetTime,omitempty"`
+}
+
+// ForkSpec identifies the service a service was forked from.
+type ForkSpec struct {
+	ProjectID string `json:"projectId"`
+	ServiceID string `json:"serviceId"`
+	IsStandby bool   `json:"isStandby"`
+}
+
+// PeerVPC is the peer of a peering connection.
+type PeerVPC struct {
+	ID         string `json:"id"`
+	AccountID  string `json:"accountId"`
+	RegionCode string `json:"regionCode"`
+	CIDR       string `json:"cidr"`
+}
+
+// PeeringConnection peers a VPC with a VPC of another cloud account.
+type PeeringConnection struct {
+	ID           string   `json:"id"`
+	VPCID        string   `json:"vpcId"`
+	PeerVPC      *PeerVPC `json:"peerVpc"`
+	ErrorMessage string   `json:"errorMessage"`
+	Status       string   `json:"status"`
+}
+
+// Plan is a size of a product, with its price in a region.
+type Plan struct {
+	ID         string  `json:"id"`
+	ProductID  string  `json:"productId"`
+	Price      float64 `json:"price"`
+	MilliCPU   int64   `json:"milliCPU"`
+	MemoryGB   int64   `json:"memoryGB"`
+	StorageGB  int64   `json:"storageGB"`
+	RegionCode string  `json:"regionCode"`
+}
+
+// Product is a product offered by Timescale.
+type Product struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Plans       []*Plan `json:"plans"`
+}
+
+// RecoveryWindow is the range of times a service can be restored to, in RFC 3339 format.
+type RecoveryWindow struct {
+	Earliest string `json:"earliest"`
+	Latest   string `json:"latest"`
+}
+
+// Resource is a node of a service.
+type Resource struct {
+	ID   string       `json:"id"`
+	Spec ResourceSpec `json:"spec"`
+}
+
+// ResourceConfig sizes a service. Sizes are given as strings, e.g. "500" millicores or "2" GB.
+type ResourceConfig struct {
+	MilliCPU     string `json:"milliCPU,omitempty"`
+	MemoryGB     string `json:"memoryGB,omitempty"`
+	StorageGB    string `json:"storageGB,omitempty"`
+	ReplicaCount string `json:"replicaCount,omitempty"`
+}
+
+// ResourceSpec is the size of a node.
+type ResourceSpec struct {
+	MilliCPU  int64 `json:"milliCPU"`
+	MemoryGB  int64 `json:"memoryGB"`
+	StorageGB int64 `json:"storageGB"`
+}
+
+// Service is a database service of a project.
+type Service struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"projectId"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Created   string `json:"created"`
+	Status    string `json:"status"`
+	// The status of the HA replica, empty when the service has none.
+	ReplicaStatus     string            `json:"replicaStatus"`
+	RegionCode        string            `json:"regionCode"`
+	VPCID             string            `json:"vpcId"`
+	AutoscaleSettings AutoscaleSettings `json:"autoscaleSettings"`
+	Spec              ServiceSpec       `json:"spec"`
+	Resources         []*Resource       `json:"resources"`
+	VPCEndpoint       *VPCEndpoint      `json:"vpcEndpoint"`
+	// The service this one was forked from, if any.
+	ForkedFromID *ForkSpec `json:"forkedFromId"`
+}
+
+// ServiceSpec is how to connect to a service.
+type ServiceSpec struct {
+	Hostname                string `json:"hostname"`
+	Username                string `json:"username"`
+	Port                    int64  `json:"port"`
+	DefaultDBName           string `json:"defaultDBName"`
+	PoolerHostName          string `json:"poolerHostName"`
+	PoolerPort              int64  `json:"poolerPort"`
+	ConnectionPoolerEnabled bool   `json:"connectionPoolerEnabled"`
+}
+
+// VPC is a virtual private cloud services can be attached to.
+type VPC struct {
+	ID                 string               `json:"id"`
+	ProvisionedID      string               `json:"provisionedId"`
+	ProjectID          string               `json:"projectId"`
+	CIDR               string               `json:"cidr"`
+	Name               string               `json:"name"`
+	Created            string               `json:"created"`
+	Updated            string               `json:"updated"`
+	PeeringConnections []*PeeringConnection `json:"peeringConnections"`
+	ErrorMessage       string               `json:"errorMessage"`
+	Status             string               `json:"status"`
+	RegionCode         string               `json:"regionCode"`
+}
+
+// VPCEndpoint is the address of a service in its VPC.
+type VPCEndpoint struct {
+	Host  string `json:"host"`
+	Port  int64  `json:"port"`
+	VPCID string `json:"vpcId"`
+}
+
+// attachServiceToVPCDocument is the AttachServiceToVPC mutation.
+const attachServiceToVPCDocument = `mutation AttachServiceToVPC ($projectId: ID!, $serviceId: ID!, $vpcId: ID!) {
+  attachServiceToVpc(data: {serviceId:$serviceId,projectId:$projectId,vpcId:$vpcId})
+}`
+
+// attachServiceToVPCVariables are the variables of the AttachServiceToVPC mutation.
+type attachServiceToVPCVariables struct {
+	ProjectID string `json:"projectId"`
+	ServiceID string `json:"serviceId"`
+	VPCID     string `json:"vpcId"`
+}
+
+// attachServiceToVPCData is the data of the AttachServiceToVPC mutation.
+type attachServiceToVPCData struct {
+	AttachServiceToVPC bool `json:"attachServiceToVpc"`
+}
+
+// attachServiceToVPC sends the AttachServiceToVPC mutation.
+func (c *Client) attachServiceToVPC(ctx context.Context, variables attachServiceToVPCVariables) (*attachServiceToVPCData, error) {
+	return execute[attachServiceToVPCData](ctx, c, "AttachServiceToVPC", attachServiceToVPCDocument, variables)
+}
+
+// createServiceDocument is the CreateService mutation.
+const createServiceDocument = `mutation CreateService ($projectId: ID!, $name: String!, $type: Type!, $resourceConfig: ResourceConfig, $regionCode: String!, $vpcId: ID, $forkConfig: ForkConfig, $enableConnectionPooler: Boolean) {
+  createService(data: {projectId:$projectId,name:$name,type:$type,resourceConfig:$resourceConfig,regionCode:$regionCode,forkConfig:$forkConfig,enableConnectionPooler:$enableConnectionPooler,vpcId:$vpcId}) {
+    initialPassword
+    service {
+      ... Service
+    }
+  }
+}
+fragment Service on Service {
+  id
+  projectId
+  name
+  type
+  created
+  status
+  replicaStatus
+  regionCode
+  vpcId
+  autoscaleSettings {
+    enabled
+  }
+  spec {
+    ... on TimescaleDBServiceSpec {
+      hostname
+      username
+      port
+      defaultDBName
+      poolerHostName
+      poolerPort
+      connectionPoolerEnabled
+    }
+  }
+  resources {
+    id
+    spec {
+      ... on ResourceNode {
+        milliCPU
+        memoryGB
+        storageGB
+      }
+    }
+  }
+  vpcEndpoint {
+    host
+    port
+    vpcId
+  }
+  forkedFromId {
+    projectId
+    serviceId
+    isStandby
+  }
+}`
+
+// createServiceVariables are the variables of the CreateService mutation.
+type createServiceVariables struct {
+	ProjectID              string          `json:"projectId"`
+	Name                   string          `json:"name"`
+	Type                   string          `json:"type"`
+	ResourceConfig         *ResourceConfig `json:"resourceConfig,omitempty"`
+	RegionCode             string          `json:"regionCode"`
+	VPCID                  string          `json:"vpcId,omitempty"`
+	ForkConfig             *ForkConfig     `json:"forkConfig,omitempty"`
+	EnableConnectionPooler bool            `json:"enableConnectionPooler,omitempty"`
+}
+
+// createServiceData is the data of the CreateService mutation.
+type createServiceData struct {
+	CreateService CreateServicePayload `json:"createService"`
+}
+
+// createService sends the CreateService mutation.
+func (c *Client) createService(ctx context.Context, variables createServiceVariables) (*createServiceData, error) {
+	return execute[createServiceData](ctx, c, "CreateService", createServiceDocument, variables)
+}
+
+// createVPCDocument is the CreateVPC mutation.
+const createVPCDocument = `mutation CreateVPC ($projectId: ID!, $name: String!, $cidr: String!, $regionCode: String!) {
+  createVpc(data: {projectId:$projectId,name:$name,cidr:$cidr,cloudProvider:AWS,regionCode:$regionCode}) {
+    ... VPC
+  }
+}
+fragment VPC on VPC {
+  id
+  provisionedId
+  projectId
+  cidr
+  name
+  created
+  updated
+  peeringConnections {
+    id
+    vpcId
+    peerVpc {
+      id
+      accountId
+      regionCode
+      cidr
+    }
+    errorMessage
+    status
+  }
+  errorMessage
+  status
+  regionCode
+}`
+
+// createVPCVariables are the variables of the CreateVPC mutation.
+type createVPCVariables struct {
+	ProjectID  string `json:"projectId"`
+	Name       string `json:"name"`
+	CIDR       string `json:"cidr"`
+	RegionCode string `json:"regionCode"`
+}
+
+// createVPCData is the data of the CreateVPC mutation.
+type createVPCData struct {
+	CreateVPC VPC `json:"createVpc"`
+}
+
+// createVPC sends the CreateVPC mutation.
+func (c *Client) createVPC(ctx context.Context, variables createVPCVariables) (*createVPCData, error) {
+	return execute[createVPCData](ctx, c, "CreateVPC", createVPCDocument, variables)
+}
+
+// deleteServiceDocument is the DeleteService mutation.
+const deleteServiceDocument = `mutation DeleteService ($projectId: ID!, $serviceId: ID!) {
+  deleteService(data: {serviceId:$serviceId,projectId:$projectId}) {
+    ... Service
+  }
+}
+fragment Service on Service {
+  id
+  projectId
+  name
+  type
+  created
+  status
+  replicaStatus
+  regionCode
+  vpcId
+  autoscaleSettings {
+    enabled
+  }
+  spec {
+    ... on TimescaleDBServiceSpec {
+      hostname
+      username
+      port
+      defaultDBName
+      poolerHostName
+      poolerPort
+      connectionPoolerEnabled
+    }
+  }
+  resources {
+    id
+    spec {
+      ... on ResourceNode {
+        milliCPU
+        memoryGB
+        storageGB
+      }
+    }
+  }
+  vpcEndpoint {
+    host
+    port
+    vpcId
+  }
+  forkedFromId {
+    projectId
+    serviceId
+    isStandby
+  }
+}`
+
+// deleteServiceVariables are the variables of the DeleteService mutation.
+type deleteServiceVariables struct {
+	ProjectID string `json:"projectId"`
+	ServiceID string `json:"serviceId"`
+}
+
+// deleteServiceData is the data of the DeleteService mutation.
+type deleteServiceData struct {
+	DeleteService *Service `json:"deleteService"`
+}
+
+// deleteService sends the DeleteService mutation.
+func (c *Client) deleteService(ctx context.Context, variables deleteServiceVariables) (*deleteServiceData, error) {
+	return execute[deleteServiceData](ctx, c, "DeleteService", deleteServiceDocument, variables)
+}
+
+// deleteVPCDocument is the DeleteVPC mutation.
+const deleteVPCDocument = `mutation DeleteVPC ($projectId: ID!, $vpcId: ID!) {
+  deleteVpc(data: {vpcId:$vpcId,projectId:$projectId})
+}`
+
+// deleteVPCVariables are the variables of the DeleteVPC mutation.
+type deleteVPCVariables struct {
+	ProjectID string `json:"projectId"`
+	VPCID     string `json:"vpcId"`
+}
+
+// deleteVPCData is the data of the DeleteVPC mutation.
+type deleteVPCData struct {
+	DeleteVPC bool `json:"deleteVpc"`
+}
+
+// deleteVPC sends the DeleteVPC mutation.
+func (c *Client) deleteVPC(ctx context.Context, variables deleteVPCVariables) (*deleteVPCData, error) {
+	return execute[deleteVPCData](ctx, c, "DeleteVPC", deleteVPCDocument, variables)
+}
+
+// detachServiceFromVPCDocument is the DetachServiceFromVPC mutation.
+const detachServiceFromVPCDocument = `mutation DetachServiceFromVPC ($projectId: ID!, $serviceId: ID!, $vpcId: ID!) {
+  detachServiceFromVpc(data: {serviceId:$serviceId,projectId:$projectId,vpcId:$vpcId})
+}`
+
+// detachServiceFromVPCVariables are the variables of the DetachServiceFromVPC mutation.
+type detachServiceFromVPCVariables struct {
+	ProjectID string `json:"projectId"`
+	ServiceID string `json:"serviceId"`
+	VPCID     string `json:"vpcId"`
+}
+
+// detachServiceFromVPCData is the data of the DetachServiceFromVPC mutation.
+type detachServiceFromVPCData struct {
+	DetachServiceFromVPC bool `json:"detachServiceFromVpc"`
+}
+
+// detachServiceFromVPC sends the DetachServiceFromVPC mutation.
+func (c *Client) detachServiceFromVPC(ctx context.Context, variables detachServiceFromVPCVariables) (*detachServiceFromVPCData, error) {
+	return execute[detachServiceFromVPCData](ctx, c, "DetachServiceFromVPC", detachServiceFromVPCDocument, variables)
+}
+
+// getAllServicesDocument is the GetAllServices query.
+const getAllServicesDocument = `query GetAllServices ($projectId: ID!) {
+  getAllServices(projectId: $projectId) {
+    ... Service
+  }
+}
+fragment Service on Service {
+  id
+  projectId
+  name
+  type
+  created
+  status
+  replicaStatus
+  regionCode
+  vpcId
+  autoscaleSettings {
+    enabled
+  }
+  spec {
+    ... on TimescaleDBServiceSpec {
+      hostname
+      username
+      port
+      defaultDBName
+      poolerHostName
+      poolerPort
+      connectionPoolerEnabled
+    }
+  }
+  resources {
+    id
+    spec {
+      ... on ResourceNode {
+        milliCPU
+        memoryGB
+        storageGB
+      }
+    }
+  }
+  vpcEndpoint {
+    host
+    port
+    vpcId
+  }
+  forkedFromId {
+    projectId
+    serviceId
+    isStandby
+  }
+}`
+
+// getAllServicesVariables are the variables of the GetAllServices query.
+type getAllServicesVariables struct {
+	ProjectID string `json:"projectId"`
+}
+
+// getAllServicesData is the data of the GetAllServices query.
+type getAllServicesData struct {
+	GetAllServices []*Service `json:"getAllServices"`
+}
+
+// getAllServices sends the GetAllServices query.
+func (c *Client) getAllServices(ctx context.Context, variables getAllServicesVariables) (*getAllServicesData, error) {
+	return execute[getAllServicesData](ctx, c, "GetAllServices", getAllServicesDocument, variables)
+}
+
+// getAllVPCsDocument is the GetAllVPCs query.
+const getAllVPCsDocument = `query GetAllVPCs ($projectId: ID!) {
+  getAllVpcs(projectId: $projectId) {
+    ... VPC
+  }
+}
+fragment VPC on VPC {
+  id
+  provisionedId
+  projectId
+  cidr
+  name
+  created
+  updated
+  peeringConnections {
+    id
+    vpcId
+    peerVpc {
+      id
+      accountId
+      regionCode
+      cidr
+    }
+    errorMessage
+    status
+  }
+  errorMessage
+  status
+  regionCode
+}`
+
+// getAllVPCsVariables are the variables of the GetAllVPCs query.
+type getAllVPCsVariables struct {
+	ProjectID string `json:"projectId"`
+}
+
+// getAllVPCsData is the data of the GetAllVPCs query.
+type getAllVPCsData struct {
+	GetAllVPCs []*VPC `json:"getAllVpcs"`
+}
+
+// getAllVPCs sends the GetAllVPCs query.
+func (c *Client) getAllVPCs(ctx context.Context, variables getAllVPCsVariables) (*getAllVPCsData, error) {
+	return execute[getAllVPCsData](ctx, c, "GetAllVPCs", getAllVPCsDocument, variables)
+}
+
+// getJWTForClientCredentialsDocument is the GetJWTForClientCredentials query.
+const getJWTForClientCredentialsDocument = `query GetJWTForClientCredentials ($accessKey: String!, $secretKey: String!) {
+  getJWTForClientCredentials(data: {accessKey:$accessKey,secretKey:$secretKey})
+}`
+
+// getJWTForClientCredentialsVariables are the variables of the GetJWTForClientCredentials query.
+type getJWTForClientCredentialsVariables struct {
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+}
+
+// getJWTForClientCredentialsData is the data of the GetJWTForClientCredentials query.
+type getJWTForClientCredentialsData struct {
+	GetJWTForClientCredentials string `json:"getJWTForClientCredentials"`
+}
+
+// getJWTForClientCredentials sends the GetJWTForClientCredentials query.
+func (c *Client) getJWTForClientCredentials(ctx context.Context, variables getJWTForClientCredentialsVariables) (*getJWTForClientCredentialsData, error) {
+	return execute[getJWTForClientCredentialsData](ctx, c, "GetJWTForClientCredentials", getJWTForClientCredentialsDocument, variables)
+}
+
+// getProductsDocument is the GetProducts query.
+const getProductsDocument = `query GetProducts {
+  products {
+    id
+    name
+    description
+    plans {
+      id
+      productId
+      price
+      milliCPU
+      memoryGB
+      storageGB
+      regionCode
+    }
+  }
+}`
+
+// getProductsData is the data of the GetProducts query.
+type getProductsData struct {
+	Products []*Product `json:"products"`
+}
+
+// getProducts sends the GetProducts query.
+func (c *Client) getProducts(ctx context.Context) (*getProductsData, error) {
+	return execute[getProductsData](ctx, c, "GetProducts", getProductsDocument, nil)
+}
+
+// getServiceDocument is the GetService query.
+const getServiceDocument = `query GetService ($projectId: ID!, $serviceId: ID!) {
+  getService(data: {serviceId:$serviceId,projectId:$projectId}) {
+    ... Service
+  }
+}
+fragment Service on Service {
+  id
+  projectId
+  name
+  type
+  created
+  status
+  replicaStatus
+  regionCode
+  vpcId
+  autoscaleSettings {
+    enabled
+  }
+  spec {
+    ... on TimescaleDBServiceSpec {
+      hostname
+      username
+      port
+      defaultDBName
+      poolerHostName
+      poolerPort
+      connectionPoolerEnabled
+    }
+  }
+  resources {
+    id
+    spec {
+      ... on ResourceNode {
+        milliCPU
+        memoryGB
+        storageGB
+      }
+    }
+  }
+  vpcEndpoint {
+    host
+    port
+    vpcId
+  }
+  forkedFromId {
+    projectId
+    serviceId
+    isStandby
+  }
+}`
+
+// getServiceVariables are the variables of the GetService query.
+type getServiceVariables struct {
+	ProjectID string `json:"projectId"`
+	ServiceID string `json:"serviceId"`
+}
+
+// getServiceData is the data of the GetService query.
+type getServiceData struct {
+	GetService *Service `json:"getService"`
+}
+
+// getService sends the GetService query.
+func (c *Client) getService(ctx context.Context, variables getServiceVariables) (*getServiceData, error) {
+	return execute[getServiceData](ctx, c, "GetService", getServiceDocument, variables)
+}
+
+// getServiceRecoveryWindowDocument is the GetServiceRecoveryWindow query.
+const getServiceRecoveryWindowDocument = `query GetServiceRecoveryWindow ($projectId: ID!, $serviceId: ID!) {
+  getServiceRecoveryWindow(data: {serviceId:$serviceId,projectId:$projectId}) {
+    earliest
+    latest
+  }
+}`
+
+// getServiceRecoveryWindowVariables are the variables of the GetServiceRecoveryWindow query.
+type getServiceRecoveryWindowVariables struct {
+	ProjectID string `json:"projectId"`
+	ServiceID string `json:"serviceId"`
+}
+
+// getServiceRecoveryWindowData is the data of the GetServiceRecoveryWindow query.
+type getServiceRecoveryWindowData struct {
+	// The range of times a service can be restored to with the PITR fork strategy.
+	GetServiceRecoveryWindow *RecoveryWindow `json:"getServiceRecoveryWindow"`
+}
+
+// getServiceRecoveryWindow sends the GetServiceRecoveryWindow query.
+func (c *Client) getServiceRecoveryWindow(ctx context.Context, variables getServiceRecoveryWindowVariables) (*getServiceRecoveryWindowData, error) {
+	return execute[getServiceRecoveryWindowData](ctx, c, "GetServiceRecoveryWindow", getServiceRecoveryWindowDocument, variables)
+}
+
+// getVPCDocument is the GetVPC query.
+const getVPCDocument = `query GetVPC ($vpcId: ID!) {
+  getVpc(vpcId: $vpcId) {
+    ... VPC
+  }
+}
+fragment VPC on VPC {
+  id
+  provisionedId
+  projectId
+  cidr
+  name
+  created
+  updated
+  peeringConnections {
+    id
+    vpcId
+    peerVpc {
+      id
+      accountId
+      regionCode
+      cidr
+    }
+    errorMessage
+    status
+  }
+  errorMessage
+  status
+  regionCode
+}`
+
+// getVPCVariables are the variables of the GetVPC query.
+type getVPCVariables struct {
+	VPCID string `json:"vpcId"`
+}
+
+// getVPCData is the data of the GetVPC query.
+type getVPCData struct {
+	GetVPC *VPC `json:"getVpc"`
+}
+
+// getVPC sends the GetVPC query.
+func (c *Client) getVPC(ctx context.Context, variables getVPCVariables) (*getVPCData, error) {
+	return execute[getVPCData](ctx, c, "GetVPC", getVPCDocument, variables)
+}
+
+// getVPCByNameDocument is the GetVPCByName query.
+const getVPCByNameDocument = `query GetVPCByName ($projectId: ID!, $name: String!) {
+  getVpcByName(data: {projectId:$projectId,vpcName:$name}) {
+    ... VPC
+  }
+}
+fragment VPC on VPC {
+  id
+  provisionedId
+  projectId
+  cidr
+  name
+  created
+  updated
+  peeringConnections {
+    id
+    vpcId
+    peerVpc {
+      id
+      accountId
+      regionCode
+      cidr
+    }
+    errorMessage
+    status
+  }
+  errorMessage
+  status
+  regionCode
+}`
+
+// getVPCByNameVariables are the variables of the GetVPCByName query.
+type getVPCByNameVariables struct {
+	ProjectID string `json:"projectId"`
+	Name      string `json:"name"`
+}
+
+// getVPCByNameData is the data of the GetVPCByName query.
+type getVPCByNameData struct {
+	GetVPCByName *VPC `json:"getVpcByName"`
+}
+
+// getVPCByName sends the GetVPCByName query.
+func (c *Client) getVPCByName(ctx context.Context, variables getVPCByNameVariables) (*getVPCByNameData, error) {
+	return execute[getVPCByNameData](ctx, c, "GetVPCByName", getVPCByNameDocument, variables)
+}
+
+// renameServiceDocument is the RenameService mutation.
+const renameServiceDocument = `mutation RenameService ($projectId: ID!, $serviceId: ID!, $newName: String!) {
+  renameService(data: {serviceId:$serviceId,projectId:$projectId,newName:$newName})
+}`
+
+// renameServiceVariables are the variables of the RenameService mutation.
+type renameServiceVariables struct {
+	ProjectID string `json:"projectId"`
+	ServiceID string `json:"serviceId"`
+	NewName   string `json:"newName"`
+}
+
+// renameServiceData is the data of the RenameService mutation.
+type renameServiceData struct {
+	RenameService bool `json:"renameService"`
+}
+
+// renameService sends the RenameService mutation.
+func (c *Client) renameService(ctx context.Context, variables renameServiceVariables) (*renameServiceData, error) {
+	return execute[renameServiceData](ctx, c, "RenameService", renameServiceDocument, variables)
+}
+
+// renameVPCDocument is the RenameVPC mutation.
+const renameVPCDocument = `mutation RenameVPC ($projectId: ID!, $forgeVpcId: ID!, $newName: String!) {
+  renameVpc(data: {projectId:$projectId,forgeVpcId:$forgeVpcId,newName:$newName})
+}`
+
+// renameVPCVariables are the variables of the RenameVPC mutation.
+type renameVPCVariables struct {
+	ProjectID  string `json:"projectId"`
+	ForgeVPCID string `json:"forgeVpcId"`
+	NewName    string `json:"newName"`
+}
+
+// renameVPCData is the data of the RenameVPC mutation.
+type renameVPCData struct {
+	RenameVPC bool `json:"renameVpc"`
+}
+
+// renameVPC sends the RenameVPC mutation.
+func (c *Client) renameVPC(ctx context.Context, variables renameVPCVariables) (*renameVPCData, error) {
+	return execute[renameVPCData](ctx, c, "RenameVPC", renameVPCDocument, variables)
+}
+
+// resizeInstanceDocument is the ResizeInstance mutation.
+const resizeInstanceDocument = `mutation ResizeInstance ($projectId: ID!, $serviceId: ID!, $config: ResourceConfig!) {
+  resizeInstance(data: {serviceId:$serviceId,projectId:$projectId,config:$config})
+}`
+
+// resizeInstanceVariables are the variables of the ResizeInstance mutation.
+type resizeInstanceVariables struct {
+	ProjectID string         `json:"projectId"`
+	ServiceID string         `json:"serviceId"`
+	Config    ResourceConfig `json:"config"`
+}
+
+// resizeInstanceData is the data of the ResizeInstance mutation.
+type resizeInstanceData struct {
+	ResizeInstance bool `json:"resizeInstance"`
+}
+
+// resizeInstance sends the ResizeInstance mutation.
+func (c *Client) resizeInstance(ctx context.Context, variables resizeInstanceVariables) (*resizeInstanceData, error) {
+	return execute[resizeInstanceData](ctx, c, "ResizeInstance", resizeInstanceDocument, variables)
+}
+
+// setReplicaCountDocument is the SetReplicaCount mutation.
+const setReplicaCountDocument = `mutation SetReplicaCount ($projectId: ID!, $serviceId: ID!, $replicaCount: Int!) {
+  setReplicaCount(data: {serviceId:$serviceId,projectId:$projectId,replicaCount:$replicaCount})
+}`
+
+// setReplicaCountVariables are the variables of the SetReplicaCount mutation.
+type setReplicaCountVariables struct {
+	ProjectID    string `json:"projectId"`
+	ServiceID    string `json:"serviceId"`
+	ReplicaCount int64  `json:"replicaCount"`
+}
+
+// setReplicaCountData is the data of the SetReplicaCount mutation.
+type setReplicaCountData struct {
+	SetReplicaCount bool `json:"setReplicaCount"`
+}
+
+// setReplicaCount sends the SetReplicaCount mutation.
+func (c *Client) setReplicaCount(ctx context.Context, variables setReplicaCountVariables) (*setReplicaCountData, error) {
+	return execute[setReplicaCountData](ctx, c, "SetReplicaCount", setReplicaCountDocument, variables)
+}
+
+// toggleConnectionPoolerDocument is the ToggleConnectionPooler mutation.
+const toggleConnectionPoolerDocument = `mutation ToggleConnectionPooler ($projectId: ID!, $serviceId: ID!, $enable: Boolean!) {
+  toggleConnectionPooler(data: {serviceId:$serviceId,projectId:$projectId,enable:$enable})
+}`
+
+// toggleConnectionPoolerVariables are the variables of the ToggleConnectionPooler mutation.
+type toggleConnectionPoolerVariables struct {
+	ProjectID string `json:"projectId"`
+	ServiceID string `json:"serviceId"`
+	Enable    bool   `json:"enable"`
+}
+
+// toggleConnectionPoolerData is the data of the ToggleConnectionPooler mutation.
+type toggleConnectionPoolerData struct {
+	ToggleConnectionPooler bool `json:"toggleConnectionPooler"`
+}
+
+// toggleConnectionPooler sends the ToggleConnectionPooler mutation.
+func (c *Client) toggleConnectionPooler(ctx context.Context, variables toggleConnectionPoolerVariables) (*toggleConnectionPoolerData, error) {
+	return execute[toggleConnectionPoolerData](ctx, c, "ToggleConnectionPooler", toggleConnectionPoolerDocument, variables)
+}
+
+// watchServiceStatusDocument is the WatchServiceStatus subscription.
+const watchServiceStatusDocument = `subscription WatchServiceStatus ($projectId: ID!, $serviceId: ID!) {
+  serviceStatusChanged(data: {serviceId:$serviceId,projectId:$projectId}) {
+    ... Service
+  }
+}
+fragment Service on Service {
+  id
+  projectId
+  name
+  type
+  created
+  status
+  replicaStatus
+  regionCode
+  vpcId
+  autoscaleSettings {
+    enabled
+  }
+  spec {
+    ... on TimescaleDBServiceSpec {
+      hostname
+      username
+      port
+      defaultDBName
+      poolerHostName
+      poolerPort
+      connectionPoolerEnabled
+    }
+  }
+  resources {
+    id
+    spec {
+      ... on ResourceNode {
+        milliCPU
+        memoryGB
+        storageGB
+      }
+    }
+  }
+  vpcEndpoint {
+    host
+    port
+    vpcId
+  }
+  forkedFromId {
+    projectId
+    serviceId
+    isStandby
+  }
+}`
+
+// watchServiceStatusVariables are the variables of the WatchServiceStatus subscription.
+type watchServiceStatusVariables struct {
+	ProjectID string `json:"projectId"`
+	ServiceID string `json:"serviceId"`
+}
+
+// watchServiceStatusData is the data of the WatchServiceStatus subscription.
+type watchServiceStatusData struct {
+	// Pushes the state of a service whenever its status changes.
+	ServiceStatusChanged Service `json:"serviceStatusChanged"`
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/timescale/terraform-provider-timescale/internal/graphqlgen"
+)
+
+var operationsConfig = graphqlgen.Config{
+	Package:    "client",
+	Schema:     "schema.graphql",
+	Extensions: []string{"schema_assumptions.graphql"},
+	Operations: "queries",
+}
+
+func TestOperations_Validate(t *testing.T) {
+	require.NoError(t, graphqlgen.Validate(operationsConfig))
+}
+
+func TestOperations_UpToDate(t *testing.T) {
+	want, err := graphqlgen.Generate(operationsConfig)
+	require.NoError(t, err)
+	got, err := os.ReadFile("operations_gen.go")
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got), "operations_gen.go is out of date, run go generate ./client")
+}
+
+// TestOperations_MatchAPI introspects the API at TIMESCALE_DEV_URL, or at
+// DefaultURL, when client credentials are set. It fails when an operation
+// does not validate against the schema the API reports, or when the code
+// generated from that schema differs from operations_gen.go.
+func TestOperations_MatchAPI(t *testing.T) {
+	accessKey, secretKey := os.Getenv("TIMESCALE_ACCESS_KEY"), os.Getenv("TIMESCALE_SECRET_KEY")
+	if accessKey == "" || secretKey == "" {
+		t.Skip("TIMESCALE_ACCESS_KEY and TIMESCALE_SECRET_KEY are not set")
+	}
+	url := DefaultURL
+	if value, ok := os.LookupEnv("TIMESCALE_DEV_URL"); ok {
+		url = value
+	}
+	ctx := context.Background()
+	c := New("", WithURL(url), WithClientCredentials(accessKey, secretKey))
+	require.NoError(t, c.Authenticate(ctx))
+	schema, err := graphqlgen.Introspect(ctx, url, http.Header{"Authorization": {"Bearer " + c.currentToken()}})
+	require.NoError(t, err)
+
+	config := operationsConfig
+	config.Schema = filepath.Join(t.TempDir(), "schema.graphql")
+	require.NoError(t, os.WriteFile(config.Schema, []byte(schema), 0o600))
+	want, err := graphqlgen.Generate(config)
+	require.NoError(t, err, "the operations do not match the API")
+	got, err := os.ReadFile("operations_gen.go")
+	require.NoError(t, err)
+	require.Equal(t, string(got), string(want), "the API changed, run make schema and go generate ./client")
+}
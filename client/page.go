@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// DefaultPageSize is the size of the pages of ListOptions without PageSize.
+const DefaultPageSize = 100
+
+// ListOptions selects a page of a list.
+type ListOptions struct {
+	// PageSize is the maximum number of items of the page, DefaultPageSize
+	// when zero.
+	PageSize int
+	// PageToken is the NextPageToken of the previous page, empty for the
+	// first page.
+	PageToken string
+}
+
+// Page is a page of a list.
+type Page[T any] struct {
+	Items []T
+	// NextPageToken gets the next page when set as the PageToken of
+	// ListOptions. It is empty on the last page.
+	NextPageToken string
+}
+
+// ListFunc returns a page of a list, e.g. Client.ListServices.
+type ListFunc[T any] func(ctx context.Context, opts ListOptions) (*Page[T], error)
+
+// ListServices returns a page of the services of the project.
+//
+// The API returns whole lists, which are paginated by the client. Pages are
+// consistent with each other as long as the list is served from the cache,
+// see WithCacheTTL.
+func (c *Client) ListServices(ctx context.Context, opts ListOptions) (*Page[*Service], error) {
+	services, err := c.GetAllServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(services, opts)
+}
+
+// ListVPCs returns a page of the VPCs of the project, paginated like
+// ListServices.
+func (c *Client) ListVPCs(ctx context.Context, opts ListOptions) (*Page[*VPC], error) {
+	vpcs, err := c.GetVPCs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(vpcs, opts)
+}
+
+// paginate returns the page of items selected by opts. Page tokens are the
+// offset of the page in the list.
+func paginate[T any](items []T, opts ListOptions) (*Page[T], error) {
+	size := opts.PageSize
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+	offset := 0
+	if opts.PageToken != "" {
+		var err error
+		offset, err = strconv.Atoi(opts.PageToken)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("invalid page token %q", opts.PageToken)
+		}
+	}
+	offset = min(offset, len(items))
+	end := min(offset+size, len(items))
+	page := &Page[T]{Items: items[offset:end]}
+	if end < len(items) {
+		page.NextPageToken = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+// Pager iterates over the pages of a list.
+type Pager[T any] struct {
+	list ListFunc[T]
+	opts ListOptions
+	done bool
+}
+
+// NewPager returns a pager over the pages of list, starting with the page
+// selected by opts, e.g. NewPager(c.ListServices, ListOptions{PageSize: 10}).
+func NewPager[T any](list ListFunc[T], opts ListOptions) *Pager[T] {
+	return &Pager[T]{list: list, opts: opts}
+}
+
+// More reports whether there are pages left.
+func (p *Pager[T]) More() bool {
+	return !p.done
+}
+
+// Next returns the items of the next page. After an error, Next can be
+// called again to retry the same page.
+func (p *Pager[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+	page, err := p.list(ctx, p.opts)
+	if err != nil {
+		return nil, err
+	}
+	p.opts.PageToken = page.NextPageToken
+	p.done = page.NextPageToken == ""
+	return page.Items, nil
+}
+
+// All returns the items of all the pages of list, starting with the page
+// selected by opts.
+func All[T any](ctx context.Context, list ListFunc[T], opts ListOptions) ([]T, error) {
+	var items []T
+	for pager := NewPager(list, opts); pager.More(); {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+	}
+	return items, nil
+}
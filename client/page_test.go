@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// servicesHandler answers GetAllServices with n services.
+func servicesHandler(calls *atomic.Int32, n int) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		services := make([]string, n)
+		for i := range services {
+			services[i] = fmt.Sprintf(`{"id":"svc-%d"}`, i)
+		}
+		_, _ = w.Write([]byte(`{"data":{"getAllServices":[` + strings.Join(services, ",") + `]}}`))
+	}
+}
+
+func serviceIDs(services []*Service) []string {
+	ids := make([]string, len(services))
+	for i, service := range services {
+		ids[i] = service.ID
+	}
+	return ids
+}
+
+func TestListServices_Pages(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, servicesHandler(&calls, 5), WithCacheTTL(time.Minute))
+	ctx := context.Background()
+
+	page, err := c.ListServices(ctx, ListOptions{PageSize: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"svc-0", "svc-1"}, serviceIDs(page.Items))
+	page, err = c.ListServices(ctx, ListOptions{PageSize: 2, PageToken: page.NextPageToken})
+	require.NoError(t, err)
+	require.Equal(t, []string{"svc-2", "svc-3"}, serviceIDs(page.Items))
+	page, err = c.ListServices(ctx, ListOptions{PageSize: 2, PageToken: page.NextPageToken})
+	require.NoError(t, err)
+	require.Equal(t, []string{"svc-4"}, serviceIDs(page.Items))
+	require.Empty(t, page.NextPageToken)
+	require.EqualValues(t, 1, calls.Load())
+
+	_, err = c.ListServices(ctx, ListOptions{PageToken: "next"})
+	require.ErrorContains(t, err, `invalid page token "next"`)
+}
+
+func TestPager(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, servicesHandler(&calls, 5), WithCacheTTL(time.Minute))
+	ctx := context.Background()
+
+	var pages [][]string
+	for pager := NewPager(c.ListServices, ListOptions{PageSize: 3}); pager.More(); {
+		services, err := pager.Next(ctx)
+		require.NoError(t, err)
+		pages = append(pages, serviceIDs(services))
+	}
+	require.Equal(t, [][]string{{"svc-0", "svc-1", "svc-2"}, {"svc-3", "svc-4"}}, pages)
+
+	services, err := All(ctx, c.ListServices, ListOptions{PageSize: 2})
+	require.NoError(t, err)
+	require.Len(t, services, 5)
+
+	// An empty list has a single empty page.
+	c = newTestClient(t, servicesHandler(&calls, 0))
+	services, err = All(ctx, c.ListServices, ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, services)
+}
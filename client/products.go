@@ -0,0 +1,17 @@
+package client
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// GetProducts returns the products offered by Timescale, with their plans.
+func (c *Client) GetProducts(ctx context.Context) ([]*Product, error) {
+	tflog.Trace(ctx, "Client.GetProducts")
+	data, err := c.getProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return data.Products, nil
+}
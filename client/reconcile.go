@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// reconcileTimeout bounds the lookup of a service after an ambiguous
+	// failure, which can run after the context of the creation expired.
+	reconcileTimeout = time.Minute
+	// createdClockSkew is tolerated between the clocks of the client and the
+	// API when matching the creation time of services.
+	createdClockSkew = time.Minute
+)
+
+// IsAmbiguous reports whether a mutation that failed with err may still have
+// been applied by the API, e.g. because the connection was lost or timed out
+// after the request was sent.
+func IsAmbiguous(err error) bool {
+	if err == nil || isNotSent(err) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	// The API answered, so it handled the request or refused it.
+	var gqlErr *Error
+	if errors.As(err, &gqlErr) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// findCreatedService looks for a service created by a request that failed
+// ambiguously: a service matching the request, created since the request
+// was sent. It returns nil when there is none.
+func (c *Client) findCreatedService(ctx context.Context, request CreateServiceRequest, since time.Time) (*Service, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), reconcileTimeout)
+	defer cancel()
+	services, err := c.GetAllServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var matches []*Service
+	for _, s := range services {
+		if s.Name != request.Name || (request.RegionCode != "" && s.RegionCode != request.RegionCode) {
+			continue
+		}
+		if request.ForkConfig != nil && (s.ForkedFromID == nil || s.ForkedFromID.ServiceID != request.ForkConfig.ServiceID) {
+			continue
+		}
+		// Services whose creation time cannot be read are kept, as they
+		// may be the one.
+		if created, err := time.Parse(time.RFC3339, s.Created); err == nil && created.Before(since.Add(-createdClockSkew)) {
+			continue
+		}
+		matches = append(matches, s)
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	}
+	ids := make([]string, 0, len(matches))
+	for _, s := range matches {
+		ids = append(ids, s.ID)
+	}
+	return nil, fmt.Errorf("several services named %q were created meanwhile: %s", request.Name, strings.Join(ids, ", "))
+}
+
+// reconcileCreateService adopts the service created by a request that failed
+// ambiguously with err, or returns err if there is none.
+func (c *Client) reconcileCreateService(ctx context.Context, request CreateServiceRequest, sent time.Time, err error) (*CreateServiceResponse, error) {
+	tflog.Warn(ctx, "CreateService failed after the request was sent, looking for the service it may have created", map[string]interface{}{
+		"error": err.Error(),
+	})
+	service, findErr := c.findCreatedService(ctx, request, sent)
+	if findErr != nil {
+		return nil, fmt.Errorf("%w (unable to check whether the service was created anyway: %s)", err, findErr)
+	}
+	if service == nil {
+		return nil, err
+	}
+	tflog.Warn(ctx, fmt.Sprintf("Adopting service %s, created by the failed request", service.ID))
+	return &CreateServiceResponse{Service: *service, Adopted: true}, nil
+}
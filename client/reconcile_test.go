@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAmbiguous(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"no error":        {nil, false},
+		"not sent":        {&net.OpError{Op: "dial", Err: errors.New("connection refused")}, false},
+		"canceled":        {context.Canceled, false},
+		"graphql error":   {Errors{{Message: "invalid name"}}, false},
+		"client status":   {&StatusError{StatusCode: http.StatusTooManyRequests}, false},
+		"gateway timeout": {&StatusError{StatusCode: http.StatusGatewayTimeout}, true},
+		"timeout":         {context.DeadlineExceeded, true},
+		"connection lost": {fmt.Errorf("read: %w", io.ErrUnexpectedEOF), true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.want, IsAmbiguous(test.err))
+		})
+	}
+}
+
+// createHandler answers CreateService with answer, or a 504 if it is empty,
+// counts the creations and lists the given services.
+func createHandler(t *testing.T, sends *int, answer, services string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		switch {
+		case strings.Contains(string(body), `"operationName":"CreateService"`):
+			*sends++
+			if answer == "" {
+				w.WriteHeader(http.StatusGatewayTimeout)
+				return
+			}
+			_, _ = w.Write([]byte(answer))
+		case strings.Contains(string(body), `"operationName":"GetAllServices"`):
+			_, _ = w.Write([]byte(`{"data":{"getAllServices":` + services + `}}`))
+		default:
+			t.Fatalf("unexpected request %s", body)
+		}
+	}
+}
+
+func TestCreateService_Reconciles(t *testing.T) {
+	now := time.Now().UTC()
+	recent, old := now.Format(time.RFC3339), now.Add(-time.Hour).Format(time.RFC3339)
+	request := CreateServiceRequest{Name: "ingest", RegionCode: "us-east-1"}
+
+	tests := map[string]struct {
+		answer   string
+		services string
+		adopted  string
+		err      string
+	}{
+		"adopts the created service": {
+			services: `[{"id":"old","name":"ingest","regionCode":"us-east-1","created":"` + old + `"},` +
+				`{"id":"other","name":"other","regionCode":"us-east-1","created":"` + recent + `"},` +
+				`{"id":"new","name":"ingest","regionCode":"us-east-1","created":"` + recent + `"}]`,
+			adopted: "new",
+		},
+		"fails when no service was created": {
+			services: `[{"id":"old","name":"ingest","regionCode":"us-east-1","created":"` + old + `"}]`,
+			err:      "unexpected HTTP status 504",
+		},
+		"fails when several services match": {
+			services: `[{"id":"a","name":"ingest","regionCode":"us-east-1","created":"` + recent + `"},` +
+				`{"id":"b","name":"ingest","regionCode":"us-east-1","created":"` + recent + `"}]`,
+			err: "several services named \"ingest\" were created meanwhile: a, b",
+		},
+		"does not adopt services on a conflict": {
+			answer:   `{"errors":[{"message":"service exists","extensions":{"code":"CONFLICT"}}]}`,
+			services: `[{"id":"new","name":"ingest","regionCode":"us-east-1","created":"` + recent + `"}]`,
+			err:      "service exists",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var sends int
+			c := newTestClient(t, createHandler(t, &sends, test.answer, test.services))
+			c.setToken(makeJWT(t, time.Now().Add(time.Hour), 1))
+
+			resp, err := c.CreateService(context.Background(), request)
+			// The API does not deduplicate creations, so they are never
+			// sent twice.
+			require.Equal(t, 1, sends)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, resp.Adopted)
+			require.Equal(t, test.adopted, resp.Service.ID)
+			require.Empty(t, resp.InitialPassword)
+		})
+	}
+}
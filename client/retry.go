@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	DefaultRetryMaxAttempts = 5
+	DefaultRetryMaxWait     = 30 * time.Second
+
+	retryBaseWait = 1 * time.Second
+)
+
+// idempotentOperations lists the GraphQL operations that can safely be sent
+// more than once. Any other operation is only retried when the request never
+// reached the server.
+var idempotentOperations = map[string]bool{
+	"GetService":               true,
+	"GetServiceRecoveryWindow": true,
+	"GetAllServices":           true,
+	"GetAllVPCs":               true,
+	"GetVPCByName":             true,
+	"GetVPC":                   true,
+	"GetProducts":              true,
+}
+
+// RetryPolicy controls how failed requests are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	// MaxWait caps the wait between two attempts, including waits requested
+	// by the server through the Retry-After header.
+	MaxWait time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: DefaultRetryMaxAttempts,
+		MaxWait:     DefaultRetryMaxWait,
+	}
+}
+
+// WithRetryPolicy overrides the default retry policy of the client.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		if policy.MaxAttempts < 1 {
+			policy.MaxAttempts = 1
+		}
+		if policy.MaxWait < 0 {
+			policy.MaxWait = 0
+		}
+		c.retryPolicy = policy
+	}
+}
+
+// StatusError is returned when the API answers with a status code that does
+// not carry a GraphQL response, such as 429 or 503.
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("unexpected HTTP status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected HTTP status %d: %s", e.StatusCode, e.Body)
+}
+
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// shouldRetry reports whether a request that failed with err can be sent
+// again. Idempotent operations are retried on any transient failure, other
+// operations only when the failure happened before the request was sent.
+func shouldRetry(ctx context.Context, err error, idempotent bool) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if isNotSent(err) {
+		return true
+	}
+	if !idempotent {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return isRetryableStatus(statusErr.StatusCode)
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isNotSent reports whether err shows that the request never left the client,
+// e.g. because the connection could not be established.
+func isNotSent(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// backoff returns how long to wait before the given attempt is retried.
+// A wait requested by the server takes precedence, otherwise the wait grows
+// exponentially with attempts and is jittered to spread out callers.
+func (p RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, p.MaxWait)
+	}
+	wait := time.Duration(float64(retryBaseWait) * math.Pow(2, float64(attempt-1)))
+	if wait <= 0 || wait > p.MaxWait {
+		wait = p.MaxWait
+	}
+	if wait <= 0 {
+		return 0
+	}
+	half := wait / 2
+	// Jitter does not need a cryptographically secure source.
+	return half + time.Duration(rand.Int63n(int64(half)+1)) //nolint:gosec
+}
+
+// parseRetryAfter reads a Retry-After header, given either in seconds or as
+// an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
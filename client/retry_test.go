@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	opts = append([]Option{
+		WithURL(server.URL),
+		WithToken("token"),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, MaxWait: 10 * time.Millisecond}),
+	}, opts...)
+	return New("project", opts...)
+}
+
+func TestDo_RetriesIdempotentQueries(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"getService":{"id":"svc","status":"READY"}}}`))
+	})
+
+	service, err := c.GetService(context.Background(), "svc")
+	require.NoError(t, err)
+	require.Equal(t, "READY", service.Status)
+	require.EqualValues(t, 3, calls.Load())
+}
+
+func TestDo_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	_, err := c.GetAllServices(context.Background())
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	require.Equal(t, http.StatusBadGateway, statusErr.StatusCode)
+	require.EqualValues(t, 3, calls.Load())
+}
+
+func TestDo_DoesNotRetrySentMutations(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	_, err := c.DeleteService(context.Background(), "svc")
+	require.Error(t, err)
+	require.EqualValues(t, 1, calls.Load())
+}
+
+func TestDo_HonorsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"products":[]}}`))
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 2, MaxWait: 50 * time.Millisecond}))
+
+	start := time.Now()
+	_, err := c.GetProducts(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 2, calls.Load())
+	// Retry-After is capped by MaxWait.
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestShouldRetry(t *testing.T) {
+	ctx := context.Background()
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	readErr := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+
+	tests := map[string]struct {
+		err        error
+		idempotent bool
+		want       bool
+	}{
+		"dial error on mutation":  {err: dialErr, want: true},
+		"read error on mutation":  {err: readErr, want: false},
+		"read error on query":     {err: readErr, idempotent: true, want: true},
+		"503 on query":            {err: &StatusError{StatusCode: 503}, idempotent: true, want: true},
+		"503 on mutation":         {err: &StatusError{StatusCode: 503}, want: false},
+		"500 on query":            {err: &StatusError{StatusCode: 500}, idempotent: true, want: false},
+		"non network error":       {err: errors.New("boom"), idempotent: true, want: false},
+		"dns error on mutation":   {err: &net.DNSError{Err: "no such host"}, want: true},
+		"unexpected eof on query": {err: fmt.Errorf("read: %w", io.ErrUnexpectedEOF), idempotent: true, want: true},
+		"cancelled context":       {err: context.Canceled, idempotent: true, want: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.want, shouldRetry(ctx, test.err, test.idempotent))
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, 5*time.Second, parseRetryAfter("5", now))
+	require.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	require.Equal(t, time.Duration(0), parseRetryAfter("-1", now))
+	require.Equal(t, 10*time.Second, parseRetryAfter(now.Add(10*time.Second).Format(http.TimeFormat), now))
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, MaxWait: 4 * time.Second}
+	for attempt := 1; attempt <= 5; attempt++ {
+		wait := p.backoff(attempt, 0)
+		require.LessOrEqual(t, wait, p.MaxWait)
+		require.Greater(t, wait, time.Duration(0))
+	}
+	require.Equal(t, p.MaxWait, p.backoff(1, time.Minute))
+}
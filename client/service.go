@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Strategies of ForkConfig.ForkStrategy.
+const (
+	// ForkStrategyLastSnapshot forks the last snapshot of the source, the
+	// default.
+	ForkStrategyLastSnapshot = "LAST_SNAPSHOT"
+	// ForkStrategyNow forks a snapshot of the source taken on creation.
+	ForkStrategyNow = "NOW"
+	// ForkStrategyPITR forks the source as it was at ForkConfig.TargetTime.
+	ForkStrategyPITR = "PITR"
+)
+
+// CreateServiceRequest describes a service to create. Name is generated when
+// empty.
+type CreateServiceRequest struct {
+	Name     string
+	MilliCPU string
+	MemoryGB string
+	// StorageGB is used for forks, since the CreateServiceRequest expects a storage to be requested
+	// and the fork instance should match the storage size of the primary.
+	StorageGB    string
+	RegionCode   string
+	ReplicaCount string
+	VpcID        int64
+	ForkConfig   *ForkConfig
+
+	EnableConnectionPooler bool
+}
+
+// CreateServiceResponse is a created service.
+type CreateServiceResponse struct {
+	Service         Service `json:"service"`
+	InitialPassword string  `json:"initialPassword"`
+	// Adopted is set when the creation failed ambiguously and Service was
+	// found afterwards. InitialPassword is unknown then.
+	Adopted bool `json:"-"`
+}
+
+// CreateService creates a service and returns it as soon as it is queued;
+// see StatusWatcher to wait for it to be ready. The request is sent once:
+// the API does not deduplicate creations, so a service created by a request
+// that failed ambiguously is found by its name and creation time, and
+// adopted instead of being created twice.
+func (c *Client) CreateService(ctx context.Context, request CreateServiceRequest) (*CreateServiceResponse, error) {
+	tflog.Trace(ctx, "Client.CreateService")
+	if request.Name == "" {
+		r, err := rand.Int(rand.Reader, big.NewInt(90000))
+		if err != nil {
+			return nil, err
+		}
+		request.Name = fmt.Sprintf("db-%d", 10000+r.Int64())
+	}
+	if request.StorageGB == "" {
+		request.StorageGB = "50"
+	}
+
+	variables := createServiceVariables{
+		ProjectID:  c.projectID,
+		Name:       request.Name,
+		Type:       "TIMESCALEDB",
+		RegionCode: request.RegionCode,
+		ResourceConfig: &ResourceConfig{
+			MilliCPU:     request.MilliCPU,
+			StorageGB:    request.StorageGB,
+			MemoryGB:     request.MemoryGB,
+			ReplicaCount: request.ReplicaCount,
+		},
+		ForkConfig:             request.ForkConfig,
+		EnableConnectionPooler: request.EnableConnectionPooler,
+	}
+	if request.VpcID > 0 {
+		variables.VPCID = strconv.FormatInt(request.VpcID, 10)
+	}
+
+	sent := time.Now()
+	data, err := c.createService(ctx, variables)
+	if err != nil {
+		if IsAmbiguous(err) {
+			return c.reconcileCreateService(ctx, request, sent, err)
+		}
+		return nil, err
+	}
+	return &CreateServiceResponse{
+		Service:         data.CreateService.Service,
+		InitialPassword: data.CreateService.InitialPassword,
+	}, nil
+}
+
+// RenameService renames a service.
+func (c *Client) RenameService(ctx context.Context, serviceID string, newName string) error {
+	tflog.Trace(ctx, "Client.RenameService")
+	_, err := c.renameService(ctx, renameServiceVariables{
+		ProjectID: c.projectID,
+		ServiceID: serviceID,
+		NewName:   newName,
+	})
+	return err
+}
+
+// SetReplicaCount sets the number of HA replicas of a service.
+func (c *Client) SetReplicaCount(ctx context.Context, serviceID string, replicaCount int) error {
+	tflog.Trace(ctx, "Client.SetReplicaCount")
+	_, err := c.setReplicaCount(ctx, setReplicaCountVariables{
+		ProjectID:    c.projectID,
+		ServiceID:    serviceID,
+		ReplicaCount: int64(replicaCount),
+	})
+	return err
+}
+
+// ResizeInstance changes the CPU and memory of a service. The storage and
+// replica count of config are ignored.
+func (c *Client) ResizeInstance(ctx context.Context, serviceID string, config ResourceConfig) error {
+	tflog.Trace(ctx, "Client.ResizeInstance")
+	_, err := c.resizeInstance(ctx, resizeInstanceVariables{
+		ProjectID: c.projectID,
+		ServiceID: serviceID,
+		Config: ResourceConfig{
+			MilliCPU:  config.MilliCPU,
+			StorageGB: "0",
+			MemoryGB:  config.MemoryGB,
+		},
+	})
+	return err
+}
+
+// GetService returns a service, or a not found error, see IsNotFound.
+func (c *Client) GetService(ctx context.Context, id string) (*Service, error) {
+	tflog.Trace(ctx, "Client.GetService")
+	data, err := c.getService(ctx, getServiceVariables{
+		ProjectID: c.projectID,
+		ServiceID: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data.GetService == nil || data.GetService.ID == "" {
+		return nil, newNotFoundError("service %s not found", id)
+	}
+	return data.GetService, nil
+}
+
+// GetRecoveryWindow returns the range of times service id can be restored
+// to, as a fork with ForkStrategyPITR.
+//
+// The query is not known to be served by every version of the API: an
+// IsUnsupported error means the window is unavailable, and the API still
+// validates ForkConfig.TargetTime on creation.
+func (c *Client) GetRecoveryWindow(ctx context.Context, id string) (*RecoveryWindow, error) {
+	tflog.Trace(ctx, "Client.GetRecoveryWindow")
+	data, err := c.getServiceRecoveryWindow(ctx, getServiceRecoveryWindowVariables{
+		ProjectID: c.projectID,
+		ServiceID: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data.GetServiceRecoveryWindow == nil {
+		return nil, newNotFoundError("service %s not found", id)
+	}
+	return data.GetServiceRecoveryWindow, nil
+}
+
+// Contains reports whether t is within the window.
+func (w *RecoveryWindow) Contains(t time.Time) (bool, error) {
+	earliest, err := time.Parse(time.RFC3339, w.Earliest)
+	if err != nil {
+		return false, fmt.Errorf("invalid start of recovery window %q: %w", w.Earliest, err)
+	}
+	latest, err := time.Parse(time.RFC3339, w.Latest)
+	if err != nil {
+		return false, fmt.Errorf("invalid end of recovery window %q: %w", w.Latest, err)
+	}
+	return !t.Before(earliest) && !t.After(latest), nil
+}
+
+// GetAllServices returns the services of the project, see also
+// ListServices.
+func (c *Client) GetAllServices(ctx context.Context) ([]*Service, error) {
+	tflog.Trace(ctx, "Client.GetAllServices")
+	data, err := c.getAllServices(ctx, getAllServicesVariables{
+		ProjectID: c.projectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data.GetAllServices, nil
+}
+
+// DeleteService deletes a service and returns it as it was before its
+// deletion.
+func (c *Client) DeleteService(ctx context.Context, id string) (*Service, error) {
+	tflog.Trace(ctx, "Client.DeleteService")
+	data, err := c.deleteService(ctx, deleteServiceVariables{
+		ProjectID: c.projectID,
+		ServiceID: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data.DeleteService == nil {
+		return &Service{}, nil
+	}
+	return data.DeleteService, nil
+}
+
+// ToggleConnectionPooler enables or disables the connection pooler of a
+// service.
+func (c *Client) ToggleConnectionPooler(ctx context.Context, serviceID string, enable bool) error {
+	tflog.Trace(ctx, "Client.ToggleConnectionPooler")
+	_, err := c.toggleConnectionPooler(ctx, toggleConnectionPoolerVariables{
+		ProjectID: c.projectID,
+		ServiceID: serviceID,
+		Enable:    enable,
+	})
+	return err
+}
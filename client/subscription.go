@@ -0,0 +1,225 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// subscriptionProtocol is the GraphQL over WebSocket protocol spoken by the
+// client, see https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md.
+const subscriptionProtocol = "graphql-transport-ws"
+
+// subscriptionHandshakeTimeout bounds the setup of a subscription.
+const subscriptionHandshakeTimeout = 10 * time.Second
+
+// subscriptionMessage is a message of subscriptionProtocol.
+type subscriptionMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// subscriptionConn is a WebSocket safe for concurrent writes.
+type subscriptionConn struct {
+	*websocket.Conn
+	mu sync.Mutex
+}
+
+func (c *subscriptionConn) write(msg subscriptionMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.WriteJSON(msg)
+}
+
+var _ ServiceSubscriber = &Client{}
+
+// WithSubscriptions makes SubscribeService subscribe to the status of
+// services over a WebSocket to the API URL. The subscription is assumed: the
+// API does not document it, so it is disabled by default and services are
+// polled instead.
+func WithSubscriptions(enabled bool) Option {
+	return func(c *Client) {
+		c.subscriptions = enabled
+	}
+}
+
+// SubscribeService reports the state of a service whenever its status
+// changes, through a GraphQL subscription over WebSocket. It returns
+// ErrSubscriptionUnsupported unless WithSubscriptions enabled it.
+func (c *Client) SubscribeService(ctx context.Context, serviceID string) (<-chan ServiceUpdate, error) {
+	tflog.Trace(ctx, "Client.SubscribeService")
+	if !c.subscriptions || c.subscriptionsUnsupported.Load() {
+		return nil, ErrSubscriptionUnsupported
+	}
+	conn, err := c.dialSubscription(ctx)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(map[string]any{
+		"operationName": "WatchServiceStatus",
+		"query":         watchServiceStatusDocument,
+		"variables": watchServiceStatusVariables{
+			ProjectID: c.projectID,
+			ServiceID: serviceID,
+		},
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.write(subscriptionMessage{ID: serviceID, Type: "subscribe", Payload: payload}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	updates := make(chan ServiceUpdate)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.write(subscriptionMessage{ID: serviceID, Type: "complete"})
+		case <-done:
+		}
+		conn.Close()
+	}()
+	go func() {
+		defer close(updates)
+		defer close(done)
+		for {
+			service, err := c.readSubscription(conn)
+			if errors.Is(err, errSubscriptionComplete) {
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if !sendUpdate(ctx, updates, ServiceUpdate{Service: service, Err: err}) || err != nil {
+				return
+			}
+		}
+	}()
+	return updates, nil
+}
+
+// errSubscriptionComplete is returned by readSubscription when the API ends
+// the subscription.
+var errSubscriptionComplete = errors.New("subscription complete")
+
+// readSubscription returns the next state of the service pushed on conn.
+func (c *Client) readSubscription(conn *subscriptionConn) (*Service, error) {
+	for {
+		var msg subscriptionMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return nil, err
+		}
+		switch msg.Type {
+		case "ping":
+			if err := conn.write(subscriptionMessage{Type: "pong"}); err != nil {
+				return nil, err
+			}
+		case "next":
+			var resp Response[watchServiceStatusData]
+			if err := json.Unmarshal(msg.Payload, &resp); err != nil {
+				return nil, err
+			}
+			if len(resp.Errors) > 0 {
+				return nil, resp.Errors
+			}
+			if resp.Data == nil {
+				return nil, errors.New("no service found in the subscription update")
+			}
+			return &resp.Data.ServiceStatusChanged, nil
+		case "error":
+			// The API refused the subscription itself, e.g. because its
+			// schema has no such subscription.
+			c.subscriptionsUnsupported.Store(true)
+			var errs Errors
+			if err := json.Unmarshal(msg.Payload, &errs); err == nil && len(errs) > 0 {
+				return nil, errs
+			}
+			return nil, ErrSubscriptionUnsupported
+		case "complete":
+			return nil, errSubscriptionComplete
+		}
+	}
+}
+
+// dialSubscription opens a WebSocket to the API and initializes the
+// subscription protocol. It returns ErrSubscriptionUnsupported, and
+// remembers it, when the API does not accept WebSockets.
+func (c *Client) dialSubscription(ctx context.Context) (*subscriptionConn, error) {
+	// Custom transports, such as recorders, only see regular requests.
+	transport, ok := c.httpClient.Transport.(*http.Transport)
+	if c.httpClient.Transport != nil && !ok {
+		return nil, ErrSubscriptionUnsupported
+	}
+	if c.hasCredentials() {
+		if err := c.ensureToken(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: subscriptionHandshakeTimeout,
+		Subprotocols:     []string{subscriptionProtocol},
+	}
+	if transport != nil {
+		dialer.Proxy = transport.Proxy
+		dialer.TLSClientConfig = transport.TLSClientConfig
+	}
+	request, err := http.NewRequest(http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setRequestHeaders(request)
+	request.Header.Del("Content-Type")
+	url := "ws" + strings.TrimPrefix(c.url, "http")
+
+	conn, resp, err := dialer.DialContext(ctx, url, request.Header)
+	if resp != nil && resp.Body != nil {
+		resp.Body.Close()
+	}
+	if err != nil {
+		if errors.Is(err, websocket.ErrBadHandshake) {
+			c.subscriptionsUnsupported.Store(true)
+			return nil, ErrSubscriptionUnsupported
+		}
+		return nil, err
+	}
+	if conn.Subprotocol() != subscriptionProtocol {
+		c.subscriptionsUnsupported.Store(true)
+		conn.Close()
+		return nil, ErrSubscriptionUnsupported
+	}
+
+	init, err := json.Marshal(map[string]string{"Authorization": "Bearer " + c.currentToken()})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(subscriptionHandshakeTimeout))
+	var ack subscriptionMessage
+	if err := conn.WriteJSON(subscriptionMessage{Type: "connection_init", Payload: init}); err == nil {
+		err = conn.ReadJSON(&ack)
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+	if ack.Type != "connection_ack" {
+		conn.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize the subscription: %w", err)
+		}
+		c.subscriptionsUnsupported.Store(true)
+		return nil, ErrSubscriptionUnsupported
+	}
+	return &subscriptionConn{Conn: conn}, nil
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans of the client.
+const tracerName = "github.com/timescale/terraform-provider-timescale/client"
+
+// Attributes set on the spans of the client.
+const (
+	operationNameKey = attribute.Key("graphql.operation.name")
+	projectIDKey     = attribute.Key("timescale.project_id")
+	requestIDKey     = attribute.Key("timescale.request_id")
+	attemptsKey      = attribute.Key("timescale.attempts")
+	cacheHitKey      = attribute.Key("timescale.cache_hit")
+	serviceIDKey     = attribute.Key("timescale.service_id")
+	serviceStatusKey = attribute.Key("timescale.service_status")
+	httpStatusKey    = attribute.Key("http.response.status_code")
+)
+
+// tracer returns the tracer of the client. Spans are dropped unless the
+// program installs a tracer provider with otel.SetTracerProvider.
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// injectTraceContext propagates the span of ctx to the API through the
+// request headers, with the propagator installed by the program.
+func injectTraceContext(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// endSpan records the outcome of a GraphQL call on its span: err if the
+// call failed, or the GraphQL errors found in the response data.
+func endSpan(span trace.Span, data []byte, err error) {
+	defer span.End()
+	if errs := responseErrors(data); err == nil && len(errs) > 0 {
+		err = errs
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
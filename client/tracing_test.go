@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestDo_RecordsSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	calls := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case calls == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case calls == 2:
+			_, _ = w.Write([]byte(`{"data":{"getService":{"id":"svc","status":"READY"}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"errors":[{"message":"service not found","extensions":{"code":"NOT_FOUND"}}]}`))
+		}
+	})
+	c.setToken(makeJWT(t, time.Now().Add(time.Hour), 1))
+
+	_, err := c.GetService(context.Background(), "svc")
+	require.NoError(t, err)
+	_, err = c.GetService(context.Background(), "missing")
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	attrs := func(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+		m := map[attribute.Key]attribute.Value{}
+		for _, kv := range span.Attributes() {
+			m[kv.Key] = kv.Value
+		}
+		return m
+	}
+
+	ok := attrs(spans[0])
+	require.Equal(t, "graphql GetService", spans[0].Name())
+	require.Equal(t, "GetService", ok[operationNameKey].AsString())
+	require.Equal(t, "project", ok[projectIDKey].AsString())
+	require.NotEmpty(t, ok[requestIDKey].AsString())
+	require.EqualValues(t, 2, ok[attemptsKey].AsInt64())
+	require.EqualValues(t, http.StatusOK, ok[httpStatusKey].AsInt64())
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+	require.Contains(t, spans[1].Status().Description, "service not found")
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds each request, from connection to the end of the
+	// response.
+	DefaultTimeout = 30 * time.Second
+	// DefaultKeepAlive is how long idle connections are kept open for reuse.
+	DefaultKeepAlive = 90 * time.Second
+)
+
+// TransportConfig describes how the client connects to the API.
+type TransportConfig struct {
+	// ProxyURL is the proxy requests go through. When empty, the proxy is
+	// read from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment
+	// variables.
+	ProxyURL string
+	// CABundle holds PEM certificates trusted in addition to the system
+	// ones, e.g. the certificate of a TLS-inspecting proxy.
+	CABundle []byte
+	// ClientCertificate and ClientKey hold the PEM certificate and key
+	// presented for mutual TLS.
+	ClientCertificate []byte
+	ClientKey         []byte
+	// KeepAlive is how long idle connections are kept open for reuse. Zero
+	// opens a new connection for every request.
+	KeepAlive time.Duration
+}
+
+// NewTransport returns a transport connecting as described by config.
+func NewTransport(config TransportConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.ProxyURL != "" {
+		proxy, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(config.CABundle) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(config.CABundle) {
+			return nil, errors.New("the CA bundle holds no PEM certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	switch {
+	case len(config.ClientCertificate) > 0 && len(config.ClientKey) > 0:
+		cert, err := tls.X509KeyPair(config.ClientCertificate, config.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	case len(config.ClientCertificate) > 0 || len(config.ClientKey) > 0:
+		return nil, errors.New("a client certificate needs both a certificate and a key")
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if config.KeepAlive > 0 {
+		transport.IdleConnTimeout = config.KeepAlive
+	} else {
+		transport.DisableKeepAlives = true
+	}
+	return transport, nil
+}
+
+// WithTimeout bounds each request, from connection to the end of the
+// response. Zero means no timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const productsResponse = `{"data":{"products":[]}}`
+
+// newTLSClient returns a client of an HTTPS server, connecting as described
+// by config.
+func newTLSClient(t *testing.T, server *httptest.Server, config TransportConfig) *Client {
+	t.Helper()
+	transport, err := NewTransport(config)
+	require.NoError(t, err)
+	c := New("project", WithToken("token"),
+		WithURL(server.URL),
+		WithTransport(transport),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	c.setToken(makeJWT(t, time.Now().Add(time.Hour), 1))
+	return c
+}
+
+func certificatePEM(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+// newClientCertificate returns a self-signed client certificate and its key
+// as PEM.
+func newClientCertificate(t *testing.T) (*x509.Certificate, []byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return cert, certificatePEM(cert), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestTransport_CABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(productsResponse))
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := newTLSClient(t, server, TransportConfig{}).GetProducts(context.Background())
+	require.ErrorContains(t, err, "certificate")
+
+	c := newTLSClient(t, server, TransportConfig{CABundle: certificatePEM(server.Certificate())})
+	_, err = c.GetProducts(context.Background())
+	require.NoError(t, err)
+
+	_, err = NewTransport(TransportConfig{CABundle: []byte("not a certificate")})
+	require.ErrorContains(t, err, "no PEM certificate")
+}
+
+func TestTransport_ClientCertificate(t *testing.T) {
+	clientCert, certPEM, keyPEM := newClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "terraform", r.TLS.PeerCertificates[0].Subject.CommonName)
+		_, _ = w.Write([]byte(productsResponse))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	ca := certificatePEM(server.Certificate())
+
+	_, err := newTLSClient(t, server, TransportConfig{CABundle: ca}).GetProducts(context.Background())
+	require.Error(t, err)
+
+	c := newTLSClient(t, server, TransportConfig{CABundle: ca, ClientCertificate: certPEM, ClientKey: keyPEM})
+	_, err = c.GetProducts(context.Background())
+	require.NoError(t, err)
+
+	_, err = NewTransport(TransportConfig{ClientCertificate: certPEM})
+	require.ErrorContains(t, err, "both a certificate and a key")
+}
+
+func TestTransport_Proxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		_, _ = w.Write([]byte(productsResponse))
+	}))
+	t.Cleanup(proxy.Close)
+
+	transport, err := NewTransport(TransportConfig{ProxyURL: proxy.URL})
+	require.NoError(t, err)
+	c := New("project", WithToken("token"),
+		WithURL("http://api.timescale.invalid/api/query"), WithTransport(transport))
+	c.setToken(makeJWT(t, time.Now().Add(time.Hour), 1))
+	_, err = c.GetProducts(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"http://api.timescale.invalid/api/query"}, proxied)
+}
+
+func TestTransport_KeepAlive(t *testing.T) {
+	transport, err := NewTransport(TransportConfig{KeepAlive: time.Minute})
+	require.NoError(t, err)
+	require.False(t, transport.DisableKeepAlives)
+	require.Equal(t, time.Minute, transport.IdleConnTimeout)
+
+	transport, err = NewTransport(TransportConfig{})
+	require.NoError(t, err)
+	require.True(t, transport.DisableKeepAlives)
+}
+
+func TestWithTimeout(t *testing.T) {
+	release := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+	}, WithTimeout(20*time.Millisecond), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	t.Cleanup(func() { close(release) })
+	c.setToken(makeJWT(t, time.Now().Add(time.Hour), 1))
+
+	_, err := c.GetProducts(context.Background())
+	require.ErrorContains(t, err, "Client.Timeout")
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// GetVPCs returns the VPCs of the project, see also ListVPCs.
+func (c *Client) GetVPCs(ctx context.Context) ([]*VPC, error) {
+	tflog.Trace(ctx, "Client.GetVPCs")
+	data, err := c.getAllVPCs(ctx, getAllVPCsVariables{
+		ProjectID: c.projectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data.GetAllVPCs, nil
+}
+
+// GetVPCByName returns the VPC of the project with the given name, or a not
+// found error, see IsNotFound.
+func (c *Client) GetVPCByName(ctx context.Context, name string) (*VPC, error) {
+	tflog.Trace(ctx, "Client.GetVPCByName")
+	if c.cache.enabled() {
+		// Look the VPC up in the cached list, instead of sending a query for
+		// each VPC.
+		vpcs, err := c.GetVPCs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, vpc := range vpcs {
+			if vpc.Name == name {
+				return vpc, nil
+			}
+		}
+		// The cached list may predate the VPC, e.g. if it was created or
+		// renamed outside of this client: ask the API before reporting it
+		// as not found.
+		c.cache.invalidate(c.projectID)
+	}
+	data, err := c.getVPCByName(ctx, getVPCByNameVariables{
+		ProjectID: c.projectID,
+		Name:      name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data.GetVPCByName == nil {
+		return nil, newNotFoundError("vpc %s not found", name)
+	}
+	return data.GetVPCByName, nil
+}
+
+// GetVPCByID returns a VPC, or a not found error, see IsNotFound.
+func (c *Client) GetVPCByID(ctx context.Context, vpcID int64) (*VPC, error) {
+	tflog.Trace(ctx, "Client.GetVPCByID")
+	data, err := c.getVPC(ctx, getVPCVariables{
+		VPCID: strconv.FormatInt(vpcID, 10),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data.GetVPC == nil {
+		return nil, newNotFoundError("vpc %d not found", vpcID)
+	}
+	return data.GetVPC, nil
+}
+
+// AttachServiceToVPC attaches a service to a VPC.
+func (c *Client) AttachServiceToVPC(ctx context.Context, serviceID string, vpcID int64) error {
+	tflog.Trace(ctx, "Client.AttachServiceToVPC")
+	_, err := c.attachServiceToVPC(ctx, attachServiceToVPCVariables{
+		ProjectID: c.projectID,
+		ServiceID: serviceID,
+		VPCID:     strconv.FormatInt(vpcID, 10),
+	})
+	return err
+}
+
+// DetachServiceFromVPC detaches a service from a VPC.
+func (c *Client) DetachServiceFromVPC(ctx context.Context, serviceID string, vpcID int64) error {
+	tflog.Trace(ctx, "Client.DetachServiceFromVPC")
+	_, err := c.detachServiceFromVPC(ctx, detachServiceFromVPCVariables{
+		ProjectID: c.projectID,
+		ServiceID: serviceID,
+		VPCID:     strconv.FormatInt(vpcID, 10),
+	})
+	return err
+}
+
+// CreateVPC creates a VPC with the given IPv4 CIDR block. Name is generated
+// when empty.
+func (c *Client) CreateVPC(ctx context.Context, name, cidr, regionCode string) (*VPC, error) {
+	tflog.Trace(ctx, "Client.CreateVPC")
+
+	if name == "" {
+		r, err := rand.Int(rand.Reader, big.NewInt(90000))
+		if err != nil {
+			return nil, err
+		}
+		name = fmt.Sprintf("vpc-%d", 10000+r.Int64())
+
+	}
+
+	data, err := c.createVPC(ctx, createVPCVariables{
+		ProjectID:  c.projectID,
+		Name:       name,
+		CIDR:       cidr,
+		RegionCode: regionCode,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &data.CreateVPC, nil
+}
+
+// RenameVPC renames a VPC.
+func (c *Client) RenameVPC(ctx context.Context, vpcID int64, newName string) error {
+	tflog.Trace(ctx, "Client.RenameVPC")
+	_, err := c.renameVPC(ctx, renameVPCVariables{
+		ProjectID:  c.projectID,
+		ForgeVPCID: strconv.FormatInt(vpcID, 10),
+		NewName:    newName,
+	})
+	return err
+}
+
+// DeleteVPC deletes a VPC.
+func (c *Client) DeleteVPC(ctx context.Context, vpcID int64) error {
+	tflog.Trace(ctx, "Client.DeleteVPC")
+	_, err := c.deleteVPC(ctx, deleteVPCVariables{
+		ProjectID: c.projectID,
+		VPCID:     strconv.FormatInt(vpcID, 10),
+	})
+	return err
+}
@@ -0,0 +1,194 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	DefaultPollMinInterval = time.Second
+	DefaultPollMaxInterval = 10 * time.Second
+
+	// pollBackoff is the factor the poll interval grows by while the status
+	// of a service does not change.
+	pollBackoff = 1.5
+)
+
+// ServiceUpdate is the state of a service reported by a StatusWatcher, or
+// the error that ended the watch.
+type ServiceUpdate struct {
+	Service *Service
+	Err     error
+}
+
+// ServiceSubscriber is implemented by APIs pushing the changes of the status
+// of services.
+type ServiceSubscriber interface {
+	// SubscribeService reports the state of a service whenever its status
+	// changes. It returns ErrSubscriptionUnsupported when the API cannot
+	// push changes.
+	SubscribeService(ctx context.Context, serviceID string) (<-chan ServiceUpdate, error)
+}
+
+// ErrSubscriptionUnsupported is returned by SubscribeService when the API
+// does not support subscriptions.
+var ErrSubscriptionUnsupported = errors.New("the API does not support subscriptions")
+
+// PollPolicy paces the polling of a service by a StatusWatcher.
+type PollPolicy struct {
+	// MinInterval is the wait between two polls after the status changed.
+	MinInterval time.Duration
+	// MaxInterval caps the wait between two polls, which grows while the
+	// status does not change.
+	MaxInterval time.Duration
+}
+
+// DefaultPollPolicy returns the poll policy used when none is configured.
+func DefaultPollPolicy() PollPolicy {
+	return PollPolicy{
+		MinInterval: DefaultPollMinInterval,
+		MaxInterval: DefaultPollMaxInterval,
+	}
+}
+
+// StatusWatcher follows the status of services. It subscribes to their
+// changes when the API supports it, and polls them otherwise.
+type StatusWatcher struct {
+	api    API
+	policy PollPolicy
+}
+
+// NewStatusWatcher returns a watcher of the services of api, polling them
+// according to policy when the API cannot push their changes.
+func NewStatusWatcher(api API, policy PollPolicy) *StatusWatcher {
+	if policy.MinInterval <= 0 {
+		policy.MinInterval = DefaultPollMinInterval
+	}
+	if policy.MaxInterval < policy.MinInterval {
+		policy.MaxInterval = policy.MinInterval
+	}
+	return &StatusWatcher{api: api, policy: policy}
+}
+
+// Watch reports the current state of a service, then its state whenever its
+// status changes. The channel is closed when ctx is done, or after an update
+// carrying an error.
+func (w *StatusWatcher) Watch(ctx context.Context, serviceID string) <-chan ServiceUpdate {
+	updates := make(chan ServiceUpdate)
+	go func() {
+		defer close(updates)
+		status := ""
+		if subscriber, ok := w.api.(ServiceSubscriber); ok {
+			pushed, err := subscriber.SubscribeService(ctx, serviceID)
+			if err == nil {
+				var done bool
+				if status, done = w.forward(ctx, serviceID, pushed, updates); done {
+					return
+				}
+			}
+			if err != nil && !errors.Is(err, ErrSubscriptionUnsupported) {
+				tflog.Debug(ctx, "Unable to subscribe to the status of the service, polling it instead", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+		}
+		w.poll(ctx, serviceID, status, updates)
+	}()
+	return updates
+}
+
+// forward sends the current state of a service, then the updates pushed by
+// the API. It returns the last status sent, and false if the subscription
+// ended early, so that the service is polled instead.
+func (w *StatusWatcher) forward(ctx context.Context, serviceID string, pushed <-chan ServiceUpdate, updates chan<- ServiceUpdate) (string, bool) {
+	ctx, span := tracer().Start(ctx, "StatusWatcher.subscribe", trace.WithAttributes(serviceIDKey.String(serviceID)))
+	defer span.End()
+
+	// The subscription only reports changes, so the current state is read
+	// once it is set up.
+	service, err := w.api.GetService(ctx, serviceID)
+	if !sendUpdate(ctx, updates, ServiceUpdate{Service: service, Err: err}) || err != nil {
+		return "", true
+	}
+	status := service.Status
+	for {
+		select {
+		case <-ctx.Done():
+			return status, true
+		case update, ok := <-pushed:
+			if !ok || update.Err != nil {
+				if update.Err != nil {
+					span.RecordError(update.Err)
+					tflog.Debug(ctx, "The subscription to the status of the service failed, polling it instead", map[string]interface{}{
+						"error": update.Err.Error(),
+					})
+				}
+				return status, ctx.Err() != nil
+			}
+			status = update.Service.Status
+			span.AddEvent("status", trace.WithAttributes(serviceStatusKey.String(status)))
+			if !sendUpdate(ctx, updates, update) {
+				return status, true
+			}
+		}
+	}
+}
+
+// poll sends the state of a service whenever its status differs from the
+// last one sent, polling it at intervals growing while it does not change.
+func (w *StatusWatcher) poll(ctx context.Context, serviceID string, status string, updates chan<- ServiceUpdate) {
+	interval := w.policy.MinInterval
+	for {
+		service, err := w.pollOnce(ctx, serviceID)
+		if err != nil {
+			sendUpdate(ctx, updates, ServiceUpdate{Err: err})
+			return
+		}
+		if service.Status != status {
+			status = service.Status
+			interval = w.policy.MinInterval
+			if !sendUpdate(ctx, updates, ServiceUpdate{Service: service}) {
+				return
+			}
+		} else {
+			interval = min(time.Duration(float64(interval)*pollBackoff), w.policy.MaxInterval)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// pollOnce reads the state of a service, in a span of its own.
+func (w *StatusWatcher) pollOnce(ctx context.Context, serviceID string) (*Service, error) {
+	ctx, span := tracer().Start(ctx, "StatusWatcher.poll", trace.WithAttributes(serviceIDKey.String(serviceID)))
+	defer span.End()
+	service, err := w.api.GetService(ctx, serviceID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(serviceStatusKey.String(service.Status))
+	return service, nil
+}
+
+// sendUpdate sends an update unless ctx is done first.
+func sendUpdate(ctx context.Context, updates chan<- ServiceUpdate, update ServiceUpdate) bool {
+	select {
+	case updates <- update:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// watchStatuses collects the statuses reported by a watcher until the
+// service is READY.
+func watchStatuses(t *testing.T, c *Client, policy PollPolicy) []string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var statuses []string
+	for update := range NewStatusWatcher(c, policy).Watch(ctx, "svc") {
+		require.NoError(t, update.Err)
+		statuses = append(statuses, update.Service.Status)
+		if update.Service.Status == "READY" {
+			break
+		}
+	}
+	return statuses
+}
+
+// serviceHandler answers GetService with the given statuses in turn, and
+// refuses WebSockets.
+func serviceHandler(calls *atomic.Int32, statuses ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		i := min(int(calls.Add(1)), len(statuses)) - 1
+		_, _ = w.Write([]byte(`{"data":{"getService":{"id":"svc","status":"` + statuses[i] + `"}}}`))
+	}
+}
+
+func TestStatusWatcher_Polls(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, serviceHandler(&calls, "QUEUED", "QUEUED", "QUEUED", "CONFIGURING", "READY"))
+	c.setToken(makeJWT(t, time.Now().Add(time.Hour), 1))
+	policy := PollPolicy{MinInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+
+	require.Equal(t, []string{"QUEUED", "CONFIGURING", "READY"}, watchStatuses(t, c, policy))
+	require.EqualValues(t, 5, calls.Load())
+}
+
+func TestStatusWatcher_PollsWhenSubscriptionsAreEnabled(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, serviceHandler(&calls, "QUEUED", "READY"), WithSubscriptions(true))
+	c.setToken(makeJWT(t, time.Now().Add(time.Hour), 1))
+	policy := PollPolicy{MinInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+
+	// The handler refuses WebSockets.
+	require.Equal(t, []string{"QUEUED", "READY"}, watchStatuses(t, c, policy))
+	require.True(t, c.subscriptionsUnsupported.Load())
+}
+
+func TestStatusWatcher_Subscribes(t *testing.T) {
+	upgrader := websocket.Upgrader{Subprotocols: []string{subscriptionProtocol}}
+	var calls atomic.Int32
+	var subscription atomic.Value
+	poll := serviceHandler(&calls, "QUEUED")
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !websocket.IsWebSocketUpgrade(r) {
+			poll(w, r)
+			return
+		}
+		require.Contains(t, r.Header.Get("Authorization"), "Bearer ")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		var msg subscriptionMessage
+		require.NoError(t, conn.ReadJSON(&msg))
+		require.Equal(t, "connection_init", msg.Type)
+		require.NoError(t, conn.WriteJSON(subscriptionMessage{Type: "connection_ack"}))
+		require.NoError(t, conn.ReadJSON(&msg))
+		require.Equal(t, "subscribe", msg.Type)
+		subscription.Store(string(msg.Payload))
+
+		require.NoError(t, conn.WriteJSON(subscriptionMessage{Type: "ping"}))
+		for _, status := range []string{"CONFIGURING", "READY"} {
+			payload, err := json.Marshal(Response[watchServiceStatusData]{Data: &watchServiceStatusData{Service{ID: "svc", Status: status}}})
+			require.NoError(t, err)
+			require.NoError(t, conn.WriteJSON(subscriptionMessage{ID: msg.ID, Type: "next", Payload: payload}))
+		}
+		require.NoError(t, conn.ReadJSON(&msg))
+		require.Equal(t, "pong", msg.Type)
+		for msg.Type != "complete" {
+			require.NoError(t, conn.ReadJSON(&msg))
+		}
+	}, WithSubscriptions(true))
+	c.setToken(makeJWT(t, time.Now().Add(time.Hour), 1))
+
+	require.Equal(t, []string{"QUEUED", "CONFIGURING", "READY"}, watchStatuses(t, c, DefaultPollPolicy()))
+	require.EqualValues(t, 1, calls.Load())
+	require.Contains(t, subscription.Load(), `"operationName":"WatchServiceStatus"`)
+	require.Contains(t, subscription.Load(), `"serviceId":"svc"`)
+}
+
+func TestStatusWatcher_PollsWhenSubscriptionFails(t *testing.T) {
+	upgrader := websocket.Upgrader{Subprotocols: []string{subscriptionProtocol}}
+	var calls atomic.Int32
+	poll := serviceHandler(&calls, "QUEUED", "QUEUED", "READY")
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !websocket.IsWebSocketUpgrade(r) {
+			poll(w, r)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		var msg subscriptionMessage
+		require.NoError(t, conn.ReadJSON(&msg))
+		require.NoError(t, conn.WriteJSON(subscriptionMessage{Type: "connection_ack"}))
+		require.NoError(t, conn.ReadJSON(&msg))
+		require.NoError(t, conn.WriteJSON(subscriptionMessage{ID: msg.ID, Type: "error", Payload: json.RawMessage(`[{"message":"unknown subscription"}]`)}))
+	}, WithSubscriptions(true))
+	c.setToken(makeJWT(t, time.Now().Add(time.Hour), 1))
+	policy := PollPolicy{MinInterval: time.Millisecond, MaxInterval: time.Millisecond}
+
+	require.Equal(t, []string{"QUEUED", "READY"}, watchStatuses(t, c, policy))
+	require.True(t, c.subscriptionsUnsupported.Load())
+}
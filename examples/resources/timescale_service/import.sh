@@ -0,0 +1,10 @@
+# Services are imported by ID, with or without the id: prefix.
+terraform import timescale_service.ingest id:abc123
+
+# A service can be imported by name, as long as no other service of the
+# project has the same name.
+terraform import timescale_service.ingest name:ingest-prod
+
+# Services of another project than the provider's are imported with the
+# project prefix.
+terraform import timescale_service.ingest project:xyz789/name:ingest-prod
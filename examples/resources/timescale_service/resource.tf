@@ -9,3 +9,12 @@ resource "timescale_service" "test" {
 resource "timescale_service" "read_replica" {
   read_replica_source = timescale_service.test.id
 }
+
+# Fork
+resource "timescale_service" "staging" {
+  name = "staging"
+  fork_from {
+    service_id = timescale_service.test.id
+    strategy   = "NOW"
+  }
+}
@@ -0,0 +1,9 @@
+# VPCs are imported by name, with or without the name: prefix.
+terraform import timescale_vpcs.main name:main
+
+# A VPC can be imported by ID.
+terraform import timescale_vpcs.main id:1234
+
+# VPCs of another project than the provider's are imported with the project
+# prefix.
+terraform import timescale_vpcs.main project:xyz789/name:main
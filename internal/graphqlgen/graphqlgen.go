@@ -0,0 +1,531 @@
+// Package graphqlgen generates the Go types and functions of the GraphQL
+// operations sent by the client, after validating them against the schema of
+// the API.
+//
+// Each GraphQL type gets a single Go type named after it, so a type selected
+// by several operations must be selected with the same fields, typically
+// through a shared fragment. Unions and interfaces must be selected through
+// inline fragments or fragment spreads on a single type, whose fields are
+// flattened into the Go type named after the abstract type.
+package graphqlgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// Config locates the schema and the operations of a client.
+type Config struct {
+	// Package is the name of the generated package.
+	Package string
+	// Schema is the path of the schema, in the GraphQL SDL.
+	Schema string
+	// Extensions are the paths of files extending the schema with what the
+	// operations use but the API does not report yet, in the GraphQL SDL.
+	Extensions []string
+	// Operations is the directory holding the operations and the fragments
+	// they use, in .graphql files.
+	Operations string
+}
+
+// operation is a validated operation, with the fragments it uses.
+type operation struct {
+	file      string
+	def       *ast.OperationDefinition
+	fragments ast.FragmentDefinitionList
+}
+
+// Validate reports every operation that does not validate against the
+// schema.
+func Validate(config Config) error {
+	_, _, err := load(config)
+	return err
+}
+
+// load parses the schema and the operations, and validates the operations.
+func load(config Config) (*ast.Schema, []*operation, error) {
+	var sources []*ast.Source
+	for _, file := range append([]string{config.Schema}, config.Extensions...) {
+		input, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		sources = append(sources, &ast.Source{Name: file, Input: string(input)})
+	}
+	schema, err := gqlparser.LoadSchema(sources...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(config.Operations, "*.graphql"))
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(files)
+	var operations []*operation
+	fragments := map[string]*ast.FragmentDefinition{}
+	names := map[string]string{}
+	for _, file := range files {
+		input, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		doc, err := parser.ParseQuery(&ast.Source{Name: file, Input: string(input)})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, def := range doc.Operations {
+			if def.Name == "" {
+				return nil, nil, fmt.Errorf("%s: operations must be named", file)
+			}
+			if other, ok := names[def.Name]; ok {
+				return nil, nil, fmt.Errorf("%s: operation %s is already defined in %s", file, def.Name, other)
+			}
+			names[def.Name] = file
+			operations = append(operations, &operation{file: file, def: def})
+		}
+		for _, def := range doc.Fragments {
+			if _, ok := fragments[def.Name]; ok {
+				return nil, nil, fmt.Errorf("%s: fragment %s is already defined", file, def.Name)
+			}
+			fragments[def.Name] = def
+		}
+	}
+
+	var errs []error
+	used := map[string]bool{}
+	for _, op := range operations {
+		op.fragments = usedFragments(op.def.SelectionSet, fragments, map[string]bool{})
+		for _, fragment := range op.fragments {
+			used[fragment.Name] = true
+		}
+		doc := &ast.QueryDocument{Operations: ast.OperationList{op.def}, Fragments: op.fragments}
+		for _, err := range validator.Validate(schema, doc) {
+			err.SetFile(op.file)
+			errs = append(errs, err)
+		}
+	}
+	var unused []string
+	for name := range fragments {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	for _, name := range unused {
+		errs = append(errs, fmt.Errorf("%s: fragment %s is not used", fragments[name].Position.Src.Name, name))
+	}
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+	sort.Slice(operations, func(i, j int) bool { return operations[i].def.Name < operations[j].def.Name })
+	return schema, operations, nil
+}
+
+// usedFragments returns the fragments spread in a selection set, directly or
+// not, sorted by name.
+func usedFragments(set ast.SelectionSet, fragments map[string]*ast.FragmentDefinition, seen map[string]bool) ast.FragmentDefinitionList {
+	var used ast.FragmentDefinitionList
+	for _, selection := range set {
+		switch s := selection.(type) {
+		case *ast.Field:
+			used = append(used, usedFragments(s.SelectionSet, fragments, seen)...)
+		case *ast.InlineFragment:
+			used = append(used, usedFragments(s.SelectionSet, fragments, seen)...)
+		case *ast.FragmentSpread:
+			fragment, ok := fragments[s.Name]
+			if !ok || seen[s.Name] {
+				continue
+			}
+			seen[s.Name] = true
+			used = append(used, fragment)
+			used = append(used, usedFragments(fragment.SelectionSet, fragments, seen)...)
+		}
+	}
+	sort.Slice(used, func(i, j int) bool { return used[i].Name < used[j].Name })
+	return used
+}
+
+// Generate returns the Go source of the operations of config.
+func Generate(config Config) ([]byte, error) {
+	schema, operations, err := load(config)
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{schema: schema, types: map[string]*goType{}}
+	var ops bytes.Buffer
+	for _, op := range operations {
+		if err := g.operation(&ops, op); err != nil {
+			return nil, fmt.Errorf("%s: %w", op.file, err)
+		}
+	}
+
+	var out bytes.Buffer
+	inputs := []string{filepath.Base(config.Schema)}
+	for _, file := range config.Extensions {
+		inputs = append(inputs, filepath.Base(file))
+	}
+	fmt.Fprintf(&out, "// Code generated by graphqlgen from %s and %s. DO NOT EDIT.\n\n",
+		strings.Join(inputs, ", "), filepath.ToSlash(filepath.Join(filepath.Base(config.Operations), "*.graphql")))
+	fmt.Fprintf(&out, "package %s\n\nimport \"context\"\n", config.Package)
+	names := make([]string, 0, len(g.types))
+	for name := range g.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g.types[name].write(&out)
+	}
+	out.Write(ops.Bytes())
+	return format.Source(out.Bytes())
+}
+
+// goType is a generated struct.
+type goType struct {
+	name        string
+	description string
+	fields      []goField
+	// from is the operation the type was first generated for.
+	from string
+}
+
+type goField struct {
+	name        string
+	typ         string
+	tag         string
+	description string
+}
+
+func (t *goType) write(out *bytes.Buffer) {
+	out.WriteString("\n")
+	writeComment(out, "", t.description)
+	fmt.Fprintf(out, "type %s struct {\n", t.name)
+	for _, f := range t.fields {
+		writeComment(out, "\t", f.description)
+		fmt.Fprintf(out, "\t%s %s `json:%q`\n", f.name, f.typ, f.tag)
+	}
+	out.WriteString("}\n")
+}
+
+func writeComment(out *bytes.Buffer, indent, text string) {
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		fmt.Fprintf(out, "%s// %s\n", indent, strings.TrimSpace(line))
+	}
+}
+
+// sameFields reports whether two types have the same fields.
+func (t *goType) sameFields(other *goType) bool {
+	if len(t.fields) != len(other.fields) {
+		return false
+	}
+	for i := range t.fields {
+		if t.fields[i].name != other.fields[i].name || t.fields[i].typ != other.fields[i].typ || t.fields[i].tag != other.fields[i].tag {
+			return false
+		}
+	}
+	return true
+}
+
+type generator struct {
+	schema *ast.Schema
+	types  map[string]*goType
+	// current is the operation being generated.
+	current string
+}
+
+// operation writes the document, the variables and data types, and the
+// function of an operation.
+func (g *generator) operation(out *bytes.Buffer, op *operation) error {
+	g.current = op.def.Name
+	name := lowerFirst(op.def.Name)
+	kind := string(op.def.Operation)
+
+	var document bytes.Buffer
+	formatter.NewFormatter(&document, formatter.WithIndent("  ")).FormatQueryDocument(&ast.QueryDocument{
+		Operations: ast.OperationList{op.def},
+		Fragments:  op.fragments,
+	})
+	fmt.Fprintf(out, "\n// %sDocument is the %s %s.\nconst %sDocument = `%s`\n",
+		name, op.def.Name, kind, name, strings.TrimSpace(document.String()))
+
+	variables := "nil"
+	params := ""
+	if len(op.def.VariableDefinitions) > 0 {
+		t := &goType{
+			name:        name + "Variables",
+			description: fmt.Sprintf("%sVariables are the variables of the %s %s.", name, op.def.Name, kind),
+		}
+		for _, v := range op.def.VariableDefinitions {
+			typ, err := g.inputType(v.Type)
+			if err != nil {
+				return fmt.Errorf("variable $%s: %w", v.Variable, err)
+			}
+			tag := v.Variable
+			if !v.Type.NonNull {
+				tag += ",omitempty"
+			}
+			t.fields = append(t.fields, goField{name: goName(v.Variable), typ: typ, tag: tag})
+		}
+		t.write(out)
+		variables = "variables"
+		params = ", variables " + t.name
+	}
+
+	root := g.schema.Query
+	switch op.def.Operation {
+	case ast.Mutation:
+		root = g.schema.Mutation
+	case ast.Subscription:
+		root = g.schema.Subscription
+	}
+	fields, err := g.fields(root, op.def.SelectionSet)
+	if err != nil {
+		return err
+	}
+	data := &goType{
+		name:        name + "Data",
+		description: fmt.Sprintf("%sData is the data of the %s %s.", name, op.def.Name, kind),
+		fields:      fields,
+	}
+	data.write(out)
+
+	// Subscriptions are not sent over HTTP.
+	if op.def.Operation == ast.Subscription {
+		return nil
+	}
+	fmt.Fprintf(out, "\n// %s sends the %s %s.\n", name, op.def.Name, kind)
+	fmt.Fprintf(out, "func (c *Client) %s(ctx context.Context%s) (*%s, error) {\n", name, params, data.name)
+	fmt.Fprintf(out, "\treturn execute[%s](ctx, c, %q, %sDocument, %s)\n}\n", data.name, op.def.Name, name, variables)
+	return nil
+}
+
+// fields returns the fields of the Go type of a selection on a GraphQL type.
+func (g *generator) fields(def *ast.Definition, set ast.SelectionSet) ([]goField, error) {
+	selected, err := flatten(def, set)
+	if err != nil {
+		return nil, err
+	}
+	var fields []goField
+	for _, f := range selected {
+		typ, err := g.outputType(f.Definition.Type, f.SelectionSet, false)
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s: %w", def.Name, f.Name, err)
+		}
+		fields = append(fields, goField{
+			name:        goName(f.Alias),
+			typ:         typ,
+			tag:         f.Alias,
+			description: f.Definition.Description,
+		})
+	}
+	return fields, nil
+}
+
+// flatten returns the fields of a selection set, with the fields of its
+// fragments. Fields selected several times are merged.
+func flatten(def *ast.Definition, set ast.SelectionSet) ([]*ast.Field, error) {
+	var fields []*ast.Field
+	byAlias := map[string]*ast.Field{}
+	condition := ""
+	var walk func(set ast.SelectionSet) error
+	walk = func(set ast.SelectionSet) error {
+		for _, selection := range set {
+			var typeCondition string
+			var nested ast.SelectionSet
+			switch s := selection.(type) {
+			case *ast.Field:
+				if f, ok := byAlias[s.Alias]; ok {
+					f.SelectionSet = append(f.SelectionSet, s.SelectionSet...)
+					continue
+				}
+				f := *s
+				f.SelectionSet = append(ast.SelectionSet(nil), s.SelectionSet...)
+				byAlias[s.Alias] = &f
+				fields = append(fields, &f)
+				continue
+			case *ast.InlineFragment:
+				typeCondition, nested = s.TypeCondition, s.SelectionSet
+			case *ast.FragmentSpread:
+				typeCondition, nested = s.Definition.TypeCondition, s.Definition.SelectionSet
+			}
+			if typeCondition != "" && typeCondition != def.Name {
+				if condition != "" && condition != typeCondition {
+					return fmt.Errorf("selections on both %s and %s of %s are not supported", condition, typeCondition, def.Name)
+				}
+				condition = typeCondition
+			}
+			if err := walk(nested); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fields, walk(set)
+}
+
+// outputType returns the Go type of a field of a response.
+func (g *generator) outputType(t *ast.Type, set ast.SelectionSet, inList bool) (string, error) {
+	if t.Elem != nil {
+		elem, err := g.outputType(t.Elem, set, true)
+		return "[]" + elem, err
+	}
+	def := g.schema.Types[t.NamedType]
+	switch def.Kind {
+	case ast.Scalar:
+		return scalarType(def.Name), nil
+	case ast.Enum:
+		return "string", nil
+	case ast.Object, ast.Union, ast.Interface:
+		fields, err := g.fields(def, set)
+		if err != nil {
+			return "", err
+		}
+		typ := &goType{name: def.Name, description: def.Description, fields: fields, from: g.current}
+		if err := g.define(typ); err != nil {
+			return "", err
+		}
+		if inList || !t.NonNull {
+			return "*" + typ.name, nil
+		}
+		return typ.name, nil
+	}
+	return "", fmt.Errorf("unsupported type %s", def.Name)
+}
+
+// inputType returns the Go type of a variable or of a field of an input
+// type.
+func (g *generator) inputType(t *ast.Type) (string, error) {
+	if t.Elem != nil {
+		elem, err := g.inputType(t.Elem)
+		return "[]" + elem, err
+	}
+	def := g.schema.Types[t.NamedType]
+	switch def.Kind {
+	case ast.Scalar:
+		return scalarType(def.Name), nil
+	case ast.Enum:
+		return "string", nil
+	case ast.InputObject:
+		typ := &goType{name: def.Name, description: def.Description, from: g.current}
+		if _, ok := g.types[def.Name]; !ok {
+			// Register the type first, for recursive input types.
+			g.types[def.Name] = typ
+			for _, f := range def.Fields {
+				ft, err := g.inputType(f.Type)
+				if err != nil {
+					return "", fmt.Errorf("field %s.%s: %w", def.Name, f.Name, err)
+				}
+				tag := f.Name
+				if !f.Type.NonNull {
+					tag += ",omitempty"
+				}
+				typ.fields = append(typ.fields, goField{name: goName(f.Name), typ: ft, tag: tag, description: f.Description})
+			}
+		}
+		if !t.NonNull {
+			return "*" + def.Name, nil
+		}
+		return def.Name, nil
+	}
+	return "", fmt.Errorf("unsupported input type %s", def.Name)
+}
+
+// define registers a Go type, unless an identical one already is.
+func (g *generator) define(typ *goType) error {
+	other, ok := g.types[typ.name]
+	if !ok {
+		g.types[typ.name] = typ
+		return nil
+	}
+	if !other.sameFields(typ) {
+		return fmt.Errorf("%s is selected with other fields than in %s, select it through a shared fragment", typ.name, other.from)
+	}
+	return nil
+}
+
+func scalarType(name string) string {
+	switch name {
+	case "Int":
+		return "int64"
+	case "Float":
+		return "float64"
+	case "Boolean":
+		return "bool"
+	}
+	// ID, String and custom scalars.
+	return "string"
+}
+
+// initialisms are written in upper case in Go names.
+var initialisms = map[string]bool{
+	"api": true, "cidr": true, "cpu": true, "db": true, "gb": true, "ha": true,
+	"http": true, "id": true, "ip": true, "jwt": true, "url": true, "vpc": true,
+}
+
+// goName returns the exported Go name of a GraphQL name, e.g. VPCID for
+// vpcId and GetAllVPCs for getAllVpcs.
+func goName(name string) string {
+	var b strings.Builder
+	for _, word := range splitWords(name) {
+		if word == "" {
+			continue
+		}
+		lower := strings.ToLower(word)
+		if initialisms[lower] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		if stem, ok := strings.CutSuffix(lower, "s"); ok && initialisms[stem] {
+			b.WriteString(strings.ToUpper(stem) + "s")
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+// splitWords splits a camel case name into words, keeping runs of capitals
+// together, e.g. default, DB and Name for defaultDBName.
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		lowerToUpper := unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i])
+		endOfRun := unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || endOfRun || runes[i] == '_' {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+		if runes[i] == '_' {
+			start = i + 1
+		}
+	}
+	return append(words, string(runes[start:]))
+}
+
+// lowerFirst returns the unexported form of an operation name, e.g.
+// getVPCByName for GetVPCByName.
+func lowerFirst(name string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
@@ -0,0 +1,139 @@
+package graphqlgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `
+type Query {
+  getService(id: ID!): Service
+}
+
+type Service {
+  id: ID!
+  name: String!
+  vpcId: ID
+  spec: ServiceSpec!
+}
+
+union ServiceSpec = TimescaleDBServiceSpec
+
+type TimescaleDBServiceSpec {
+  hostname: String
+  port: Int
+}
+`
+
+// writeConfig writes the schema and the operations in a temporary directory.
+func writeConfig(t *testing.T, operations map[string]string) Config {
+	t.Helper()
+	dir := t.TempDir()
+	config := Config{
+		Package:    "client",
+		Schema:     filepath.Join(dir, "schema.graphql"),
+		Operations: filepath.Join(dir, "queries"),
+	}
+	require.NoError(t, os.WriteFile(config.Schema, []byte(testSchema), 0o600))
+	require.NoError(t, os.Mkdir(config.Operations, 0o700))
+	for name, operation := range operations {
+		require.NoError(t, os.WriteFile(filepath.Join(config.Operations, name), []byte(operation), 0o600))
+	}
+	return config
+}
+
+func TestGenerate(t *testing.T) {
+	config := writeConfig(t, map[string]string{
+		"get_service.graphql": `query GetService($id: ID!) {
+  getService(id: $id) {
+    id
+    vpcId
+    spec {
+      ... on TimescaleDBServiceSpec { hostname port }
+    }
+  }
+}`,
+	})
+	out, err := Generate(config)
+	require.NoError(t, err)
+	code := string(out)
+	require.Contains(t, code, "type getServiceVariables struct")
+	require.Contains(t, code, "type getServiceData struct")
+	require.Contains(t, code, "func (c *Client) getService(ctx context.Context, variables getServiceVariables) (*getServiceData, error)")
+	require.Regexp(t, `VPCID\s+string\s+`+"`json:\"vpcId\"`", code)
+	require.Regexp(t, `Port\s+int64\s+`, code)
+}
+
+func TestGenerate_Extensions(t *testing.T) {
+	config := writeConfig(t, map[string]string{
+		"get_service.graphql": `query GetService($id: ID!) { getService(id: $id) { id status } }`,
+	})
+	require.ErrorContains(t, Validate(config), `Cannot query field "status" on type "Service"`)
+
+	extension := filepath.Join(filepath.Dir(config.Schema), "assumptions.graphql")
+	require.NoError(t, os.WriteFile(extension, []byte("extend type Service { status: String }"), 0o600))
+	config.Extensions = []string{extension}
+	out, err := Generate(config)
+	require.NoError(t, err)
+	require.Contains(t, string(out), "// Code generated by graphqlgen from schema.graphql, assumptions.graphql and queries/*.graphql. DO NOT EDIT.")
+	require.Regexp(t, `Status\s+string\s+`, string(out))
+
+	// An extension declaring what the schema already has fails to load.
+	require.NoError(t, os.WriteFile(extension, []byte("extend type Service { name: String! }"), 0o600))
+	require.Error(t, Validate(config))
+}
+
+func TestValidate_Errors(t *testing.T) {
+	for name, test := range map[string]struct {
+		operations map[string]string
+		err        string
+	}{
+		"unknown field": {
+			operations: map[string]string{"get_service.graphql": `query GetService($id: ID!) { getService(id: $id) { id storageGB } }`},
+			err:        `Cannot query field "storageGB" on type "Service"`,
+		},
+		"unknown argument type": {
+			operations: map[string]string{"get_service.graphql": `query GetService($id: Int!) { getService(id: $id) { id } }`},
+			err:        `Variable "$id" of type "Int!" used in position expecting type "ID!"`,
+		},
+		"unused fragment": {
+			operations: map[string]string{
+				"get_service.graphql": `query GetService($id: ID!) { getService(id: $id) { id } }`,
+				"fragments.graphql":   `fragment Service on Service { id name }`,
+			},
+			err: "fragment Service is not used",
+		},
+		"different selections": {
+			operations: map[string]string{
+				"get_service.graphql":  `query GetService($id: ID!) { getService(id: $id) { id } }`,
+				"get_service2.graphql": `query GetServiceName($id: ID!) { getService(id: $id) { id name } }`,
+			},
+			err: "Service is selected with other fields than in",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := writeConfig(t, test.operations)
+			_, err := Generate(config)
+			require.ErrorContains(t, err, test.err)
+		})
+	}
+}
+
+func TestGoName(t *testing.T) {
+	for name, want := range map[string]string{
+		"id":                         "ID",
+		"vpcId":                      "VPCID",
+		"getAllVpcs":                 "GetAllVPCs",
+		"defaultDBName":              "DefaultDBName",
+		"storageGB":                  "StorageGB",
+		"milliCPU":                   "MilliCPU",
+		"getJWTForClientCredentials": "GetJWTForClientCredentials",
+		"forge_vpc_id":               "ForgeVPCID",
+		"replicaStatus":              "ReplicaStatus",
+	} {
+		require.Equal(t, want, goName(name), name)
+	}
+}
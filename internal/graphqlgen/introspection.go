@@ -0,0 +1,315 @@
+package graphqlgen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// IntrospectionQuery asks a GraphQL API for its schema.
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      kind
+      name
+      description
+      fields(includeDeprecated: true) {
+        name
+        description
+        args { ...InputValue }
+        type { ...TypeRef }
+        isDeprecated
+        deprecationReason
+      }
+      inputFields { ...InputValue }
+      interfaces { ...TypeRef }
+      enumValues(includeDeprecated: true) {
+        name
+        description
+        isDeprecated
+        deprecationReason
+      }
+      possibleTypes { ...TypeRef }
+    }
+  }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } }
+}
+`
+
+// Introspect fetches the schema of the GraphQL API at url with
+// IntrospectionQuery, and returns it in the GraphQL SDL. header is added to
+// the request, e.g. to authenticate it.
+func Introspect(ctx context.Context, url string, header http.Header) (string, error) {
+	body, err := json.Marshal(map[string]string{"query": IntrospectionQuery})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("introspection failed with status %d: %s", resp.StatusCode, data)
+	}
+	return SchemaFromIntrospection(data)
+}
+
+// introspection is the response to IntrospectionQuery.
+type introspection struct {
+	Data struct {
+		Schema *struct {
+			QueryType        *typeRef   `json:"queryType"`
+			MutationType     *typeRef   `json:"mutationType"`
+			SubscriptionType *typeRef   `json:"subscriptionType"`
+			Types            []fullType `json:"types"`
+		} `json:"__schema"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type fullType struct {
+	Kind          string       `json:"kind"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	Fields        []field      `json:"fields"`
+	InputFields   []inputValue `json:"inputFields"`
+	Interfaces    []typeRef    `json:"interfaces"`
+	EnumValues    []enumValue  `json:"enumValues"`
+	PossibleTypes []typeRef    `json:"possibleTypes"`
+}
+
+type field struct {
+	Name              string       `json:"name"`
+	Description       string       `json:"description"`
+	Args              []inputValue `json:"args"`
+	Type              typeRef      `json:"type"`
+	IsDeprecated      bool         `json:"isDeprecated"`
+	DeprecationReason string       `json:"deprecationReason"`
+}
+
+type inputValue struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Type         typeRef `json:"type"`
+	DefaultValue *string `json:"defaultValue"`
+}
+
+type enumValue struct {
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	IsDeprecated      bool   `json:"isDeprecated"`
+	DeprecationReason string `json:"deprecationReason"`
+}
+
+type typeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *typeRef `json:"ofType"`
+}
+
+// builtInScalars are the scalars every schema has, which are not written.
+var builtInScalars = map[string]bool{"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true}
+
+// SchemaFromIntrospection converts a response to IntrospectionQuery to the
+// GraphQL SDL. Types are sorted by name, and directive definitions are left
+// out.
+func SchemaFromIntrospection(data []byte) (string, error) {
+	var resp introspection
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Errors) > 0 {
+		return "", fmt.Errorf("introspection failed: %s", resp.Errors[0].Message)
+	}
+	schema := resp.Data.Schema
+	if schema == nil || schema.QueryType == nil {
+		return "", errors.New("introspection returned no schema")
+	}
+
+	doc := &ast.SchemaDocument{}
+	root := &ast.SchemaDefinition{}
+	for _, op := range []struct {
+		operation ast.Operation
+		ref       *typeRef
+	}{
+		{ast.Query, schema.QueryType},
+		{ast.Mutation, schema.MutationType},
+		{ast.Subscription, schema.SubscriptionType},
+	} {
+		if op.ref != nil {
+			root.OperationTypes = append(root.OperationTypes, &ast.OperationTypeDefinition{Operation: op.operation, Type: op.ref.Name})
+		}
+	}
+	doc.Schema = ast.SchemaDefinitionList{root}
+
+	types := schema.Types
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	for _, t := range types {
+		if strings.HasPrefix(t.Name, "__") || builtInScalars[t.Name] {
+			continue
+		}
+		def, err := definition(t)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", t.Name, err)
+		}
+		doc.Definitions = append(doc.Definitions, def)
+	}
+
+	var out strings.Builder
+	formatter.NewFormatter(&out, formatter.WithIndent("  ")).FormatSchemaDocument(doc)
+	return out.String(), nil
+}
+
+// definition converts an introspected type to its definition.
+func definition(t fullType) (*ast.Definition, error) {
+	def := &ast.Definition{Name: t.Name, Description: t.Description}
+	switch t.Kind {
+	case "SCALAR":
+		def.Kind = ast.Scalar
+	case "OBJECT":
+		def.Kind = ast.Object
+	case "INTERFACE":
+		def.Kind = ast.Interface
+	case "UNION":
+		def.Kind = ast.Union
+	case "ENUM":
+		def.Kind = ast.Enum
+	case "INPUT_OBJECT":
+		def.Kind = ast.InputObject
+	default:
+		return nil, fmt.Errorf("unknown kind %s", t.Kind)
+	}
+	for _, i := range t.Interfaces {
+		def.Interfaces = append(def.Interfaces, i.Name)
+	}
+	if def.Kind == ast.Union {
+		for _, p := range t.PossibleTypes {
+			def.Types = append(def.Types, p.Name)
+		}
+	}
+	for _, f := range t.Fields {
+		typ, err := f.Type.astType()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		field := &ast.FieldDefinition{Name: f.Name, Description: f.Description, Type: typ}
+		for _, arg := range f.Args {
+			value, err := arg.definition()
+			if err != nil {
+				return nil, fmt.Errorf("%s(%s): %w", f.Name, arg.Name, err)
+			}
+			field.Arguments = append(field.Arguments, &ast.ArgumentDefinition{
+				Name:         value.Name,
+				Description:  value.Description,
+				Type:         value.Type,
+				DefaultValue: value.DefaultValue,
+			})
+		}
+		if f.IsDeprecated {
+			field.Directives = ast.DirectiveList{deprecated(f.DeprecationReason)}
+		}
+		def.Fields = append(def.Fields, field)
+	}
+	for _, f := range t.InputFields {
+		field, err := f.definition()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		def.Fields = append(def.Fields, field)
+	}
+	for _, v := range t.EnumValues {
+		value := &ast.EnumValueDefinition{Name: v.Name, Description: v.Description}
+		if v.IsDeprecated {
+			value.Directives = ast.DirectiveList{deprecated(v.DeprecationReason)}
+		}
+		def.EnumValues = append(def.EnumValues, value)
+	}
+	return def, nil
+}
+
+// definition converts an introspected argument or input field.
+func (v inputValue) definition() (*ast.FieldDefinition, error) {
+	typ, err := v.Type.astType()
+	if err != nil {
+		return nil, err
+	}
+	field := &ast.FieldDefinition{Name: v.Name, Description: v.Description, Type: typ}
+	if v.DefaultValue != nil {
+		// The default value is already a GraphQL literal, which the
+		// formatter writes verbatim as the Raw of an enum value.
+		field.DefaultValue = &ast.Value{Kind: ast.EnumValue, Raw: *v.DefaultValue}
+	}
+	return field, nil
+}
+
+// astType converts an introspected type reference.
+func (r typeRef) astType() (*ast.Type, error) {
+	switch r.Kind {
+	case "NON_NULL", "LIST":
+		if r.OfType == nil {
+			return nil, fmt.Errorf("%s type without ofType", r.Kind)
+		}
+		elem, err := r.OfType.astType()
+		if err != nil {
+			return nil, err
+		}
+		if r.Kind == "LIST" {
+			return ast.ListType(elem, nil), nil
+		}
+		elem.NonNull = true
+		return elem, nil
+	default:
+		if r.Name == "" {
+			return nil, fmt.Errorf("%s type without name", r.Kind)
+		}
+		return ast.NamedType(r.Name, nil), nil
+	}
+}
+
+// deprecated returns a @deprecated directive with the given reason.
+func deprecated(reason string) *ast.Directive {
+	directive := &ast.Directive{Name: "deprecated"}
+	if reason != "" {
+		directive.Arguments = ast.ArgumentList{{Name: "reason", Value: &ast.Value{Kind: ast.StringValue, Raw: reason}}}
+	}
+	return directive
+}
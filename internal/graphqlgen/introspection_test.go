@@ -0,0 +1,99 @@
+package graphqlgen
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const testIntrospection = `{"data": {"__schema": {
+  "queryType": {"name": "Query"},
+  "mutationType": null,
+  "subscriptionType": null,
+  "types": [
+    {"kind": "OBJECT", "name": "Query", "fields": [
+      {"name": "getService", "args": [
+        {"name": "id", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}},
+        {"name": "status", "type": {"kind": "ENUM", "name": "Status"}, "defaultValue": "READY"}
+      ], "type": {"kind": "OBJECT", "name": "Service"}},
+      {"name": "getAllServices", "args": [], "type": {"kind": "NON_NULL", "ofType": {"kind": "LIST", "ofType": {"kind": "NON_NULL", "ofType": {"kind": "OBJECT", "name": "Service"}}}}}
+    ]},
+    {"kind": "OBJECT", "name": "Service", "description": "A database service.", "fields": [
+      {"name": "id", "args": [], "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}},
+      {"name": "vpcId", "args": [], "type": {"kind": "SCALAR", "name": "ID"}, "isDeprecated": true, "deprecationReason": "Use vpcEndpoint."},
+      {"name": "spec", "args": [], "type": {"kind": "UNION", "name": "ServiceSpec"}}
+    ]},
+    {"kind": "UNION", "name": "ServiceSpec", "possibleTypes": [{"kind": "OBJECT", "name": "TimescaleDBServiceSpec"}]},
+    {"kind": "OBJECT", "name": "TimescaleDBServiceSpec", "fields": [
+      {"name": "port", "args": [], "type": {"kind": "SCALAR", "name": "Int"}}
+    ]},
+    {"kind": "ENUM", "name": "Status", "enumValues": [{"name": "READY"}, {"name": "PAUSED"}]},
+    {"kind": "INPUT_OBJECT", "name": "ServiceInput", "inputFields": [
+      {"name": "name", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "String"}}}
+    ]},
+    {"kind": "SCALAR", "name": "ID"},
+    {"kind": "SCALAR", "name": "Int"},
+    {"kind": "SCALAR", "name": "String"},
+    {"kind": "OBJECT", "name": "__Type", "fields": []}
+  ]
+}}}`
+
+func TestSchemaFromIntrospection(t *testing.T) {
+	sdl, err := SchemaFromIntrospection([]byte(testIntrospection))
+	require.NoError(t, err)
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: sdl})
+	require.NoError(t, err, sdl)
+
+	getService := schema.Query.Fields.ForName("getService")
+	require.Equal(t, "Service", getService.Type.String())
+	require.Equal(t, "ID!", getService.Arguments.ForName("id").Type.String())
+	require.Equal(t, "READY", getService.Arguments.ForName("status").DefaultValue.String())
+	require.Equal(t, "[Service!]!", schema.Query.Fields.ForName("getAllServices").Type.String())
+
+	service := schema.Types["Service"]
+	require.Equal(t, "A database service.", service.Description)
+	require.NotNil(t, service.Fields.ForName("vpcId").Directives.ForName("deprecated"))
+	require.Equal(t, []string{"TimescaleDBServiceSpec"}, schema.Types["ServiceSpec"].Types)
+	require.Len(t, schema.Types["Status"].EnumValues, 2)
+	require.Equal(t, ast.InputObject, schema.Types["ServiceInput"].Kind)
+	require.NotContains(t, sdl, "__Type")
+	require.NotContains(t, sdl, "scalar")
+
+	_, errs := gqlparser.LoadQuery(schema, IntrospectionQuery)
+	require.Empty(t, errs)
+}
+
+func TestSchemaFromIntrospection_Errors(t *testing.T) {
+	_, err := SchemaFromIntrospection([]byte(`{"errors": [{"message": "introspection is disabled"}]}`))
+	require.ErrorContains(t, err, "introspection is disabled")
+	_, err = SchemaFromIntrospection([]byte(`{"data": {}}`))
+	require.ErrorContains(t, err, "no schema")
+}
+
+func TestIntrospect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, IntrospectionQuery, req.Query)
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(testIntrospection))
+	}))
+	defer server.Close()
+
+	sdl, err := Introspect(context.Background(), server.URL, http.Header{"Authorization": {"Bearer token"}})
+	require.NoError(t, err)
+	require.Contains(t, sdl, "type Service")
+	_, err = Introspect(context.Background(), server.URL, nil)
+	require.ErrorContains(t, err, "status 401")
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// defaultProfile is used when no profile is selected.
+	defaultProfile = "default"
+
+	profileEnvVar               = "TIMESCALE_PROFILE"
+	sharedCredentialsFileEnvVar = "TIMESCALE_SHARED_CREDENTIALS_FILE"
+)
+
+// defaultCredentialsFile returns ~/.timescale/credentials, or an empty string
+// when the home directory is unknown.
+func defaultCredentialsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".timescale", "credentials")
+}
+
+// credentialsFile holds the profiles of a shared credentials file, such as:
+//
+//	[default]
+//	project_id = abc123
+//	access_key = ...
+//	secret_key = ...
+//
+//	[staging]
+//	project_id = def456
+//	access_key = ...
+//	secret_key = ...
+//	api_url    = https://console.staging.example.com/api/query
+type credentialsFile struct {
+	path     string
+	profiles map[string]map[string]string
+}
+
+// readCredentialsFile parses a shared credentials file. Blank lines and
+// lines starting with # or ; are ignored.
+func readCredentialsFile(path string) (*credentialsFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	file := &credentialsFile{path: path, profiles: map[string]map[string]string{}}
+	var profile map[string]string
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";"):
+		case strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]"):
+			name := strings.TrimSpace(text[1 : len(text)-1])
+			if name == "" {
+				return nil, fmt.Errorf("%s:%d: empty profile name", path, line)
+			}
+			profile = map[string]string{}
+			file.profiles[name] = profile
+		default:
+			key, value, ok := strings.Cut(text, "=")
+			if !ok {
+				return nil, fmt.Errorf("%s:%d: expected \"key = value\" or \"[profile]\"", path, line)
+			}
+			if profile == nil {
+				return nil, fmt.Errorf("%s:%d: %q is not in a profile", path, line, strings.TrimSpace(key))
+			}
+			profile[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// profileNames returns the sorted names of the profiles of the file.
+func (f *credentialsFile) profileNames() []string {
+	names := make([]string, 0, len(f.profiles))
+	for name := range f.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// credentialsProfile is the profile selected in a shared credentials file.
+type credentialsProfile struct {
+	name   string
+	path   string
+	values map[string]string
+}
+
+// loadProfile returns the selected profile. A profile that is explicitly
+// selected must exist, while the default profile is only used if found.
+// A nil profile is returned when none is used.
+func loadProfile(name, path string, explicit bool) (*credentialsProfile, error) {
+	if path == "" {
+		return nil, nil
+	}
+	file, err := readCredentialsFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the shared credentials file: %w", err)
+	}
+	values, ok := file.profiles[name]
+	if !ok {
+		if !explicit {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("profile %q is not defined in %s, available profiles are: %s",
+			name, path, strings.Join(file.profileNames(), ", "))
+	}
+	return &credentialsProfile{name: name, path: path, values: values}, nil
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/timescale/terraform-provider-timescale/internal/testserver"
+)
+
+const testCredentialsFile = `
+# Personal project
+[default]
+project_id = personal
+access_key = personal-access
+secret_key = personal-secret
+
+[team]
+project_id = "team"
+access_key = team-access
+secret_key = team-secret
+api_url    = %s
+`
+
+func writeCredentialsFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "credentials")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestReadCredentialsFile(t *testing.T) {
+	file, err := readCredentialsFile(writeCredentialsFile(t, testCredentialsFile))
+	require.NoError(t, err)
+	require.Equal(t, []string{"default", "team"}, file.profileNames())
+	require.Equal(t, "team", file.profiles["team"]["project_id"])
+	require.Equal(t, "personal-secret", file.profiles["default"]["secret_key"])
+
+	_, err = readCredentialsFile(writeCredentialsFile(t, "project_id = orphan\n"))
+	require.ErrorContains(t, err, `"project_id" is not in a profile`)
+	_, err = readCredentialsFile(writeCredentialsFile(t, "[default]\nproject_id\n"))
+	require.ErrorContains(t, err, `:2: expected "key = value"`)
+}
+
+func TestProvider_Configure_Profile(t *testing.T) {
+	personal, personalServer := testserver.Start(testserver.Options{AccessKey: "personal-access", SecretKey: "personal-secret"})
+	t.Cleanup(personalServer.Close)
+	team, teamServer := testserver.Start(testserver.Options{AccessKey: "team-access", SecretKey: "team-secret"})
+	t.Cleanup(teamServer.Close)
+	t.Setenv("TIMESCALE_DEV_URL", "")
+	t.Setenv("TIMESCALE_PROFILE", "")
+	t.Setenv("TIMESCALE_SHARED_CREDENTIALS_FILE", writeCredentialsFile(t, fmt.Sprintf(testCredentialsFile, teamServer.URL)))
+
+	empty := newProviderModel()
+	empty.ProjectID, empty.AccessKey, empty.SecretKey = types.StringNull(), types.StringNull(), types.StringNull()
+
+	// The team profile carries its own API URL.
+	model := empty
+	model.Profile = types.StringValue("team")
+	_, diags := configureProvider(t, model)
+	requireNoErrors(t, diags)
+	require.Equal(t, 1, team.Requests("GetJWTForClientCredentials"))
+
+	// The default profile is used when none is selected.
+	model = empty
+	model.APIURL = types.StringValue(personalServer.URL)
+	_, diags = configureProvider(t, model)
+	requireNoErrors(t, diags)
+	require.Equal(t, 1, personal.Requests("GetJWTForClientCredentials"))
+
+	// Environment variables take precedence over the profile.
+	t.Setenv("TIMESCALE_PROFILE", "team")
+	t.Setenv("TIMESCALE_SECRET_KEY", "wrong")
+	_, diags = configureProvider(t, empty)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Detail(), `the TIMESCALE_SECRET_KEY environment variable for the secret key`)
+	t.Setenv("TIMESCALE_SECRET_KEY", "")
+
+	// Selecting a missing profile is an error.
+	model = empty
+	model.Profile = types.StringValue("missing")
+	_, diags = configureProvider(t, model)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Detail(), `profile "missing" is not defined`)
+	require.Contains(t, diags[0].Detail(), "available profiles are: default, team")
+}
@@ -0,0 +1,21 @@
+package provider
+
+import (
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
+)
+
+// clientErrorSummary returns a diagnostic summary describing the kind of
+// error returned by the Timescale client.
+func clientErrorSummary(err error) string {
+	switch {
+	case tsClient.IsUnauthorized(err):
+		return "Authentication Error"
+	case tsClient.IsRateLimited(err):
+		return "Rate Limited"
+	case tsClient.IsNotFound(err):
+		return "Not Found"
+	case tsClient.IsConflict(err):
+		return "Conflict"
+	}
+	return "Client Error"
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Keys of the lookups of import IDs.
+const (
+	importByID   = "id"
+	importByName = "name"
+)
+
+// importID is a parsed import ID, such as name:ingest-prod, id:abc123 or
+// project:<project_id>/name:ingest-prod.
+type importID struct {
+	// projectID is empty when the ID does not name a project.
+	projectID string
+	// key is importByID or importByName.
+	key   string
+	value string
+}
+
+// parseImportID parses an import ID. IDs without a name: or id: prefix are
+// looked up by defaultKey, and may be prefixed by a project ID and a slash.
+func parseImportID(raw, defaultKey string) (importID, error) {
+	var id importID
+	rest := raw
+	if project, ok := strings.CutPrefix(rest, "project:"); ok {
+		id.projectID, rest, ok = strings.Cut(project, "/")
+		if !ok || id.projectID == "" {
+			return importID{}, fmt.Errorf("expected project:<project_id>/name:<name> or project:<project_id>/id:<id>, got %q", raw)
+		}
+	}
+	for _, key := range []string{importByID, importByName} {
+		if value, ok := strings.CutPrefix(rest, key+":"); ok {
+			id.key, id.value = key, value
+			break
+		}
+	}
+	if id.key == "" {
+		if id.projectID != "" {
+			return importID{}, fmt.Errorf("expected name:<name> or id:<id> after the project of %q", raw)
+		}
+		// <value> or <project_id>/<value>.
+		id.key, id.value = defaultKey, rest
+		if projectID, value, ok := strings.Cut(rest, "/"); ok {
+			id.projectID, id.value = projectID, value
+			if projectID == "" {
+				return importID{}, fmt.Errorf("expected a project ID before the slash of %q", raw)
+			}
+		}
+	}
+	if id.value == "" {
+		return importID{}, fmt.Errorf("expected a %s in %q", id.key, raw)
+	}
+	return id, nil
+}
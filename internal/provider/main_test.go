@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
+	"github.com/timescale/terraform-provider-timescale/client/cassette"
+	"github.com/timescale/terraform-provider-timescale/internal/testserver"
+)
+
+// TestMain runs the acceptance tests against a local stand-in of the
+// Timescale API when TIMESCALE_TEST_SERVER is set, and against recorded
+// cassettes when TIMESCALE_CASSETTE is "replay", so they need neither
+// credentials nor network access.
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	switch {
+	case os.Getenv("TIMESCALE_TEST_SERVER") != "":
+		_, server := testserver.Start(testserver.Options{
+			AccessKey:       "test-access-key",
+			SecretKey:       "test-secret-key",
+			TransitionDelay: 100 * time.Millisecond,
+			StaleReadyDelay: 200 * time.Millisecond,
+			Subscriptions:   true,
+			Products: []*tsClient.Product{{
+				ID:   "timescale",
+				Name: "Time Series",
+				Plans: []*tsClient.Plan{
+					{ID: "plan-500", ProductID: "timescale", RegionCode: "us-east-1", MilliCPU: 500, MemoryGB: 2},
+					{ID: "plan-1000", ProductID: "timescale", RegionCode: "us-east-1", MilliCPU: 1000, MemoryGB: 4},
+				},
+			}},
+		})
+		defer server.Close()
+		setTestEnv(server.URL, "test-project")
+		if err := os.Setenv("TIMESCALE_STATUS_SUBSCRIPTIONS", "true"); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	case os.Getenv("TIMESCALE_CASSETTE") == "replay":
+		// Requests never reach the URL, and credentials are redacted from
+		// the cassettes.
+		setTestEnv("https://cassette.invalid", cassette.ProjectID)
+	default:
+		return m.Run()
+	}
+	serviceReadinessPolling = tsClient.PollPolicy{MinInterval: 50 * time.Millisecond, MaxInterval: time.Second}
+	return m.Run()
+}
+
+// setTestEnv points the acceptance tests at url with test credentials.
+func setTestEnv(url, projectID string) {
+	env := map[string]string{
+		"TIMESCALE_DEV_URL":    url,
+		"TF_VAR_ts_access_key": "test-access-key",
+		"TF_VAR_ts_secret_key": "test-secret-key",
+		"TF_VAR_ts_project_id": projectID,
+	}
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
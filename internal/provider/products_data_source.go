@@ -8,7 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	tsClient "github.com/timescale/terraform-provider-timescale/internal/client"
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -24,7 +24,7 @@ func NewProductsDataSource() datasource.DataSource {
 
 // productsDataSource is the data source implementation.
 type productsDataSource struct {
-	client *tsClient.Client
+	client tsClient.API
 }
 
 // productsDataSourceModel maps the data source schema data.
@@ -58,6 +58,8 @@ func (d *productsDataSource) Metadata(_ context.Context, req datasource.Metadata
 
 // Read refreshes the Terraform state with the latest data.
 func (d *productsDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := startSpan(ctx, "productsDataSource.Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
 	var state productsDataSourceModel
 
 	products, err := d.client.GetProducts(ctx)
@@ -113,7 +115,7 @@ func (d *productsDataSource) Configure(_ context.Context, req datasource.Configu
 		return
 	}
 
-	d.client = req.ProviderData.(*tsClient.Client)
+	d.client = req.ProviderData.(tsClient.API)
 }
 
 // Schema defines the schema for the data source.
@@ -0,0 +1,16 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
+)
+
+// projectClient returns the client of the project set by a project_id
+// attribute, or the client of the provider's project when it is not set.
+func projectClient(client tsClient.API, projectID types.String) tsClient.API {
+	if projectID.IsNull() || projectID.IsUnknown() || projectID.ValueString() == "" || projectID.ValueString() == client.ProjectID() {
+		return client
+	}
+	return client.ForProject(projectID.ValueString())
+}
@@ -3,17 +3,24 @@ package provider
 import (
 	"context"
 	"fmt"
+	"os"
+	"strconv"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
-	tsClient "github.com/timescale/terraform-provider-timescale/internal/client"
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
 )
 
 // Ensure TimescaleProvider satisfies various provider interfaces.
@@ -27,6 +34,9 @@ type TimescaleProvider struct {
 	version string
 	// terraformVersion is the caller's terraform version.
 	terraformVersion string
+	// clientOptions are applied to the client, e.g. by tests to record or
+	// replay its traffic.
+	clientOptions []tsClient.Option
 }
 
 // TimescaleProviderModel describes the provider data model.
@@ -35,6 +45,33 @@ type TimescaleProviderModel struct {
 	AccessToken types.String `tfsdk:"access_token"`
 	AccessKey   types.String `tfsdk:"access_key"`
 	SecretKey   types.String `tfsdk:"secret_key"`
+
+	CredentialProcess types.String `tfsdk:"credential_process"`
+
+	Profile               types.String `tfsdk:"profile"`
+	SharedCredentialsFile types.String `tfsdk:"shared_credentials_file"`
+
+	APIURL  types.String `tfsdk:"api_url"`
+	AuthURL types.String `tfsdk:"auth_url"`
+
+	RetryMaxAttempts types.Int64  `tfsdk:"retry_max_attempts"`
+	RetryMaxWait     types.String `tfsdk:"retry_max_wait"`
+
+	LogHTTPBodies types.Bool `tfsdk:"log_http_bodies"`
+
+	CacheTTL types.String `tfsdk:"cache_ttl"`
+
+	StatusSubscriptions types.Bool `tfsdk:"status_subscriptions"`
+
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+
+	ProxyURL          types.String `tfsdk:"proxy_url"`
+	CABundle          types.String `tfsdk:"ca_bundle"`
+	ClientCertificate types.String `tfsdk:"client_certificate"`
+	ClientKey         types.String `tfsdk:"client_key"`
+	RequestTimeout    types.String `tfsdk:"request_timeout"`
+	KeepAlive         types.String `tfsdk:"keep_alive"`
 }
 
 func (p *TimescaleProvider) Metadata(ctx context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -50,27 +87,108 @@ func (p *TimescaleProvider) Schema(ctx context.Context, _ provider.SchemaRequest
 		MarkdownDescription: "The Terraform provider for [Timescale](https://console.cloud.timescale.com/).",
 		Attributes: map[string]schema.Attribute{
 			"access_token": schema.StringAttribute{
-				MarkdownDescription: "Access Token",
+				MarkdownDescription: "Access Token. Defaults to the `TIMESCALE_ACCESS_TOKEN` environment variable.",
 				Optional:            true,
 				Sensitive:           true,
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "Project ID",
-				Required:            true,
+				MarkdownDescription: "Project ID. Defaults to the `TIMESCALE_PROJECT_ID` environment variable.",
+				Optional:            true,
 			},
 			"access_key": schema.StringAttribute{
-				MarkdownDescription: "Access Key",
+				MarkdownDescription: "Access Key. Defaults to the `TIMESCALE_ACCESS_KEY` environment variable.",
 				Optional:            true,
 			},
 			"secret_key": schema.StringAttribute{
-				MarkdownDescription: "Secret Key",
+				MarkdownDescription: "Secret Key. Defaults to the `TIMESCALE_SECRET_KEY` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"credential_process": schema.StringAttribute{
+				MarkdownDescription: "Command printing client credentials as JSON, e.g. `{\"access_key\": \"...\", \"secret_key\": \"...\", \"expiration\": \"2024-01-01T12:00:00Z\"}`. The command is run through the shell, and again whenever the credentials expire. `expiration` is optional. Defaults to the `TIMESCALE_CREDENTIAL_PROCESS` environment variable.",
+				Optional:            true,
+			},
+			"profile": schema.StringAttribute{
+				MarkdownDescription: "Name of the shared credentials profile providing the settings missing from the provider block and the environment. Defaults to the `TIMESCALE_PROFILE` environment variable, then to `default`.",
+				Optional:            true,
+			},
+			"shared_credentials_file": schema.StringAttribute{
+				MarkdownDescription: "Path of the shared credentials file. Defaults to the `TIMESCALE_SHARED_CREDENTIALS_FILE` environment variable, then to `~/.timescale/credentials`.",
+				Optional:            true,
+			},
+			"api_url": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("URL of the Timescale GraphQL API. Defaults to the `TIMESCALE_DEV_URL` environment variable, then to `%s`.", tsClient.DefaultURL),
+				Optional:            true,
+				Validators:          []validator.String{urlValidator{}},
+			},
+			"auth_url": schema.StringAttribute{
+				MarkdownDescription: "URL the client credentials are exchanged against, when it differs from `api_url`.",
+				Optional:            true,
+				Validators:          []validator.String{urlValidator{}},
+			},
+			"retry_max_attempts": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum number of attempts for a request to the Timescale API, including the first one. Read operations are retried on connection failures and 429, 502, 503 and 504 responses; write operations are only retried when the request could not be sent. Defaults to %d.", tsClient.DefaultRetryMaxAttempts),
+				Optional:            true,
+				Validators:          []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"retry_max_wait": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Maximum wait between two attempts, as a duration string such as `30s`. Defaults to `%s`.", tsClient.DefaultRetryMaxWait),
+				Optional:            true,
+			},
+			"cache_ttl": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("How long the lists of services, VPCs and products are cached, as a duration string such as `1m`. The cache is shared by all the resources and data sources of the provider, and any change made by the provider to its project clears it. `0s` disables the cache. Defaults to `%s`.", tsClient.DefaultCacheTTL),
+				Optional:            true,
+			},
+			"status_subscriptions": schema.BoolAttribute{
+				MarkdownDescription: "Experimental: wait for services by subscribing to their status over a WebSocket to `api_url`, instead of polling them. The API does not document this subscription yet, and services are polled when it is refused. Defaults to the `TIMESCALE_STATUS_SUBSCRIPTIONS` environment variable, then to `false`.",
+				Optional:            true,
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum number of requests to the Timescale API in flight at once, across all resources and data sources. `0` removes the cap. Defaults to %d.", tsClient.DefaultMaxConcurrentRequests),
+				Optional:            true,
+				Validators:          []validator.Int64{int64validator.AtLeast(0)},
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum rate of requests to the Timescale API, across all resources and data sources. When the API answers with a 429 response or rate limit headers, requests are paused and the rate is halved, then recovers gradually. `0` removes the limit until the API throttles requests. Defaults to %d.", tsClient.DefaultRequestsPerSecond),
+				Optional:            true,
+				Validators:          []validator.Float64{float64validator.AtLeast(0)},
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "URL of the proxy the requests to the Timescale API go through, such as `http://proxy.example.com:3128`. Defaults to the `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables.",
+				Optional:            true,
+				Validators:          []validator.String{urlValidator{proxy: true}},
+			},
+			"ca_bundle": schema.StringAttribute{
+				MarkdownDescription: "PEM certificates trusted in addition to the system ones, e.g. the certificate of a TLS-inspecting proxy, or the path of a file holding them.",
+				Optional:            true,
+			},
+			"client_certificate": schema.StringAttribute{
+				MarkdownDescription: "PEM certificate presented to the Timescale API for mutual TLS, or the path of a file holding it. Requires `client_key`.",
+				Optional:            true,
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM private key of `client_certificate`, or the path of a file holding it.",
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Maximum duration of a request to the Timescale API, as a duration string such as `1m`. `0s` removes the timeout. Defaults to `%s`.", tsClient.DefaultTimeout),
+				Optional:            true,
+			},
+			"keep_alive": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("How long idle connections to the Timescale API are kept open for reuse, as a duration string such as `30s`. `0s` opens a new connection for every request. Defaults to `%s`.", tsClient.DefaultKeepAlive),
+				Optional:            true,
+			},
+			"log_http_bodies": schema.BoolAttribute{
+				MarkdownDescription: "Log the headers and bodies of API requests and responses at debug level, e.g. with `TF_LOG=debug`. Tokens, passwords and secret keys are masked. Defaults to `true` when the `TIMESCALE_LOG_HTTP_BODIES` environment variable is set to `true` or `1`.",
+				Optional:            true,
+			},
 		},
 	}
 }
 
+// ConfigValidators only validates the provider block. Settings can also come
+// from the environment, so the merged result is validated by Configure.
 func (p *TimescaleProvider) ConfigValidators(_ context.Context) []provider.ConfigValidator {
 	return []provider.ConfigValidator{
 		providervalidator.Conflicting(
@@ -81,13 +199,17 @@ func (p *TimescaleProvider) ConfigValidators(_ context.Context) []provider.Confi
 			path.MatchRoot("access_token"),
 			path.MatchRoot("secret_key"),
 		),
-		providervalidator.AtLeastOneOf(
+		providervalidator.Conflicting(
+			path.MatchRoot("credential_process"),
 			path.MatchRoot("access_token"),
+		),
+		providervalidator.Conflicting(
+			path.MatchRoot("credential_process"),
 			path.MatchRoot("access_key"),
 		),
 		providervalidator.RequiredTogether(
-			path.MatchRoot("access_key"),
-			path.MatchRoot("secret_key"),
+			path.MatchRoot("client_certificate"),
+			path.MatchRoot("client_key"),
 		),
 	}
 }
@@ -103,19 +225,93 @@ func (p *TimescaleProvider) Configure(ctx context.Context, req provider.Configur
 		return
 	}
 
+	creds, diags := resolveCredentials(data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	retryPolicy := tsClient.DefaultRetryPolicy()
+	if !data.RetryMaxAttempts.IsNull() {
+		retryPolicy.MaxAttempts = int(data.RetryMaxAttempts.ValueInt64())
+	}
+	retryPolicy.MaxWait = parseDuration(data.RetryMaxWait, "retry_max_wait", retryPolicy.MaxWait, &resp.Diagnostics)
+	rateLimit := tsClient.DefaultRateLimit()
+	if !data.MaxConcurrentRequests.IsNull() {
+		rateLimit.MaxConcurrentRequests = int(data.MaxConcurrentRequests.ValueInt64())
+	}
+	if !data.RequestsPerSecond.IsNull() {
+		rateLimit.RequestsPerSecond = data.RequestsPerSecond.ValueFloat64()
+	}
+	cacheTTL := parseDuration(data.CacheTTL, "cache_ttl", tsClient.DefaultCacheTTL, &resp.Diagnostics)
+	timeout := parseDuration(data.RequestTimeout, "request_timeout", tsClient.DefaultTimeout, &resp.Diagnostics)
+	transport := newTransport(data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if creds.profile != nil {
+		tflog.Info(ctx, fmt.Sprintf("Using profile %q of the shared credentials file %s", creds.profile.name, creds.profile.path))
+	}
+
 	p.terraformVersion = req.TerraformVersion
-	client := tsClient.NewClient(data.AccessToken.ValueString(), data.ProjectID.ValueString(),
-		p.version, p.terraformVersion)
-	if !data.AccessKey.IsNull() && !data.SecretKey.IsNull() {
-		err := tsClient.JWTFromCC(client, data.AccessKey.ValueString(), data.SecretKey.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get JWT from CC, got error: %s", err))
+	logBodies := data.LogHTTPBodies.ValueBool()
+	if data.LogHTTPBodies.IsNull() {
+		logBodies, _ = strconv.ParseBool(os.Getenv("TIMESCALE_LOG_HTTP_BODIES"))
+	}
+	subscriptions := data.StatusSubscriptions.ValueBool()
+	if data.StatusSubscriptions.IsNull() {
+		subscriptions, _ = strconv.ParseBool(os.Getenv("TIMESCALE_STATUS_SUBSCRIPTIONS"))
+	}
+
+	opts := []tsClient.Option{
+		tsClient.WithToken(creds.accessToken.value),
+		tsClient.WithUserAgent("terraform-provider-timescale/" + p.version + " terraform/" + p.terraformVersion),
+		tsClient.WithRetryPolicy(retryPolicy),
+		tsClient.WithBodyLogging(logBodies),
+		tsClient.WithCacheTTL(cacheTTL),
+		tsClient.WithSubscriptions(subscriptions),
+		tsClient.WithRateLimit(rateLimit),
+		tsClient.WithTransport(transport),
+		tsClient.WithTimeout(timeout),
+		tsClient.WithURL(creds.apiURL.value),
+		tsClient.WithAuthURL(creds.authURL.value),
+	}
+	var authError string
+	switch {
+	case creds.credentialProcess.isSet():
+		opts = append(opts, tsClient.WithCredentialsSource(tsClient.CredentialProcess(creds.credentialProcess.value)))
+		authError = fmt.Sprintf("Unable to get JWT from the credential process set by %s", creds.credentialProcess.source())
+	case creds.accessKey.isSet() && creds.secretKey.isSet():
+		opts = append(opts, tsClient.WithClientCredentials(creds.accessKey.value, creds.secretKey.value))
+		authError = fmt.Sprintf("Unable to get JWT from CC with the client credentials of %s", creds.keysSource())
+	}
+	opts = append(opts, p.clientOptions...)
+	client := tsClient.New(creds.projectID.value, opts...)
+	if authError != "" {
+		if err := client.Authenticate(ctx); err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("%s, got error: %s", authError, err))
 		}
 	}
 	resp.DataSourceData = client
 	resp.ResourceData = client
 }
 
+// parseDuration returns the duration set by a provider attribute, or
+// defaultValue when it is not set.
+func parseDuration(value types.String, attribute string, defaultValue time.Duration, diags *diag.Diagnostics) time.Duration {
+	if value.IsNull() {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value.ValueString())
+	if err != nil || d < 0 {
+		diags.AddAttributeError(path.Root(attribute), "Invalid Attribute Value",
+			fmt.Sprintf("%s must be a positive duration such as \"30s\", got %q", attribute, value.ValueString()))
+		return defaultValue
+	}
+	return d
+}
+
 // Resources defines the resources implemented in the provider.
 func (p *TimescaleProvider) Resources(ctx context.Context) []func() resource.Resource {
 	tflog.Trace(ctx, "TimescaleProvider.Resources")
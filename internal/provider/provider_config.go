@@ -0,0 +1,218 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Environment variables used when the matching attribute is not set in the
+// provider block.
+const (
+	projectIDEnvVar   = "TIMESCALE_PROJECT_ID"
+	accessKeyEnvVar   = "TIMESCALE_ACCESS_KEY"
+	secretKeyEnvVar   = "TIMESCALE_SECRET_KEY"
+	accessTokenEnvVar = "TIMESCALE_ACCESS_TOKEN"
+	apiURLEnvVar      = "TIMESCALE_DEV_URL"
+
+	credentialProcessEnvVar = "TIMESCALE_CREDENTIAL_PROCESS"
+)
+
+// settingOrigin tells where a provider setting comes from.
+type settingOrigin int
+
+const (
+	originUnset settingOrigin = iota
+	originBlock
+	originEnv
+	originProfile
+)
+
+// providerSetting is a provider attribute resolved from the provider block,
+// its environment variable, or the shared credentials profile, in that
+// order.
+type providerSetting struct {
+	attribute string
+	envVar    string
+	value     string
+	origin    settingOrigin
+	profile   *credentialsProfile
+}
+
+func resolveSetting(value types.String, attribute, envVar string, profile *credentialsProfile) providerSetting {
+	s := providerSetting{attribute: attribute, envVar: envVar}
+	switch {
+	case !value.IsNull():
+		s.value, s.origin = value.ValueString(), originBlock
+	case os.Getenv(envVar) != "":
+		s.value, s.origin = os.Getenv(envVar), originEnv
+	case profile != nil && profile.values[attribute] != "":
+		s.value, s.origin, s.profile = profile.values[attribute], originProfile, profile
+	}
+	return s
+}
+
+func (s providerSetting) isSet() bool {
+	return s.value != ""
+}
+
+// unset returns the setting without its value.
+func (s providerSetting) unset() providerSetting {
+	return providerSetting{attribute: s.attribute, envVar: s.envVar}
+}
+
+// source describes where the setting comes from, for diagnostics.
+func (s providerSetting) source() string {
+	switch s.origin {
+	case originEnv:
+		return fmt.Sprintf("the %s environment variable", s.envVar)
+	case originProfile:
+		return fmt.Sprintf("profile %q of %s", s.profile.name, s.profile.path)
+	}
+	return fmt.Sprintf("the %q attribute", s.attribute)
+}
+
+// howToSet describes how to provide a missing setting, for diagnostics.
+func (s providerSetting) howToSet() string {
+	return fmt.Sprintf("set the %q attribute in the provider block, the %s environment variable or %q in a shared credentials profile",
+		s.attribute, s.envVar, s.attribute)
+}
+
+// providerCredentials are the settings of the provider after merging the
+// provider block with the environment and the shared credentials file.
+type providerCredentials struct {
+	// profile is the shared credentials profile in use, if any.
+	profile *credentialsProfile
+
+	projectID   providerSetting
+	accessToken providerSetting
+	accessKey   providerSetting
+	secretKey   providerSetting
+	// credentialProcess is a command printing client credentials.
+	credentialProcess providerSetting
+	apiURL            providerSetting
+	authURL           providerSetting
+}
+
+// keysSource describes where the client credentials come from, for
+// diagnostics.
+func (c providerCredentials) keysSource() string {
+	switch {
+	case !c.secretKey.isSet() || c.accessKey.source() == c.secretKey.source():
+		return c.accessKey.source()
+	case !c.accessKey.isSet():
+		return c.secretKey.source()
+	}
+	return fmt.Sprintf("%s for the access key and %s for the secret key", c.accessKey.source(), c.secretKey.source())
+}
+
+// selectCredentials keeps a single kind of credentials among the access
+// token, the client credentials and the credential process: the one set
+// closest to the provider block. Kinds set at the same level conflict.
+func (c *providerCredentials) selectCredentials() diag.Diagnostics {
+	var diags diag.Diagnostics
+	keysOrigin := c.accessKey.origin
+	if keysOrigin == originUnset || (c.secretKey.origin != originUnset && c.secretKey.origin < keysOrigin) {
+		keysOrigin = c.secretKey.origin
+	}
+	kinds := []struct {
+		description string
+		origin      settingOrigin
+		source      string
+		unset       func()
+	}{
+		{"an access token", c.accessToken.origin, c.accessToken.source(), func() { c.accessToken = c.accessToken.unset() }},
+		{"client credentials", keysOrigin, c.keysSource(), func() { c.accessKey, c.secretKey = c.accessKey.unset(), c.secretKey.unset() }},
+		{"a credential process", c.credentialProcess.origin, c.credentialProcess.source(), func() { c.credentialProcess = c.credentialProcess.unset() }},
+	}
+
+	best := originUnset
+	for _, kind := range kinds {
+		if kind.origin != originUnset && (best == originUnset || kind.origin < best) {
+			best = kind.origin
+		}
+	}
+	var selected []string
+	for _, kind := range kinds {
+		switch {
+		case kind.origin == originUnset:
+		case kind.origin == best:
+			selected = append(selected, fmt.Sprintf("%s by %s", kind.description, kind.source))
+		default:
+			kind.unset()
+		}
+	}
+	if len(selected) > 1 {
+		diags.AddError("Conflicting Credentials",
+			fmt.Sprintf("Only one kind of credentials can be used, but %s are set.", strings.Join(selected, " and ")))
+	}
+	return diags
+}
+
+// resolveCredentials merges the provider block with the environment and the
+// shared credentials file, and validates the result. The provider block
+// takes precedence over the environment, which takes precedence over the
+// profile.
+func resolveCredentials(data TimescaleProviderModel) (providerCredentials, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var c providerCredentials
+
+	profileName := resolveSetting(data.Profile, "profile", profileEnvVar, nil)
+	credentialsPath := resolveSetting(data.SharedCredentialsFile, "shared_credentials_file", sharedCredentialsFileEnvVar, nil)
+	name, file := profileName.value, credentialsPath.value
+	if name == "" {
+		name = defaultProfile
+	}
+	if file == "" {
+		file = defaultCredentialsFile()
+	}
+	profile, err := loadProfile(name, file, profileName.isSet() || credentialsPath.isSet())
+	if err != nil {
+		diags.AddError("Invalid Shared Credentials", err.Error())
+		return c, diags
+	}
+	c.profile = profile
+
+	c.projectID = resolveSetting(data.ProjectID, "project_id", projectIDEnvVar, profile)
+	c.accessToken = resolveSetting(data.AccessToken, "access_token", accessTokenEnvVar, profile)
+	c.accessKey = resolveSetting(data.AccessKey, "access_key", accessKeyEnvVar, profile)
+	c.secretKey = resolveSetting(data.SecretKey, "secret_key", secretKeyEnvVar, profile)
+	c.apiURL = resolveSetting(data.APIURL, "api_url", apiURLEnvVar, profile)
+	c.authURL = resolveSetting(data.AuthURL, "auth_url", "", profile)
+	c.credentialProcess = resolveSetting(data.CredentialProcess, "credential_process", credentialProcessEnvVar, profile)
+
+	if !c.projectID.isSet() {
+		diags.AddAttributeError(path.Root("project_id"), "Missing Project ID",
+			fmt.Sprintf("The project ID is not configured: %s.", c.projectID.howToSet()))
+	}
+	diags.Append(c.selectCredentials()...)
+	switch {
+	case c.accessToken.isSet() || c.credentialProcess.isSet():
+	case c.accessKey.isSet() && !c.secretKey.isSet():
+		diags.AddAttributeError(path.Root("secret_key"), "Missing Secret Key",
+			fmt.Sprintf("The access key is set by %s but the secret key is missing: %s.",
+				c.accessKey.source(), c.secretKey.howToSet()))
+	case c.secretKey.isSet() && !c.accessKey.isSet():
+		diags.AddAttributeError(path.Root("access_key"), "Missing Access Key",
+			fmt.Sprintf("The secret key is set by %s but the access key is missing: %s.",
+				c.secretKey.source(), c.accessKey.howToSet()))
+	case !c.accessKey.isSet():
+		diags.AddError("Missing Credentials",
+			fmt.Sprintf("No credentials are configured: set the \"access_key\" and \"secret_key\" attributes in the provider block, the %s and %s environment variables or a shared credentials profile, provide a command printing them with the \"credential_process\" attribute or the %s environment variable, or provide an access token with the \"access_token\" attribute or the %s environment variable.",
+				accessKeyEnvVar, secretKeyEnvVar, credentialProcessEnvVar, accessTokenEnvVar))
+	}
+
+	// URLs from the provider block are validated by the schema.
+	for _, url := range []providerSetting{c.apiURL, c.authURL} {
+		if url.isSet() && url.origin != originBlock {
+			if err := validateURL(url.value); err != nil {
+				diags.AddError("Invalid API URL", fmt.Sprintf("%s is set by %s: %s", url.attribute, url.source(), err))
+			}
+		}
+	}
+	return c, diags
+}
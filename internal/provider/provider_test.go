@@ -1,14 +1,34 @@
 package provider
 
 import (
+	"context"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/stretchr/testify/require"
+
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
+	"github.com/timescale/terraform-provider-timescale/client/cassette"
+	"github.com/timescale/terraform-provider-timescale/client/fake"
+	"github.com/timescale/terraform-provider-timescale/internal/testserver"
 )
 
 const (
@@ -43,6 +63,46 @@ var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 	"timescale": providerserver.NewProtocol6WithError(New("test")()),
 }
 
+// cassetteDir holds the recorded traffic of acceptance tests.
+const cassetteDir = "testdata/cassettes"
+
+// cassetteProviderFactories returns the provider factories of an acceptance
+// test. When TIMESCALE_CASSETTE is "record", the traffic of the test is
+// recorded to a cassette named after it. When it is "replay", the recorded
+// traffic is served back instead of calling the API, and the test fails if
+// nothing was recorded for it.
+func cassetteProviderFactories(t *testing.T) map[string]func() (tfprotov6.ProviderServer, error) {
+	t.Helper()
+	path := filepath.Join(cassetteDir, t.Name()+".json")
+	var c *cassette.Cassette
+	switch mode := os.Getenv("TIMESCALE_CASSETTE"); mode {
+	case "":
+		return testAccProtoV6ProviderFactories
+	case "record":
+		c = cassette.NewRecorder(path, nil)
+		t.Cleanup(func() {
+			if !t.Skipped() {
+				require.NoError(t, c.Save())
+			}
+		})
+	case "replay":
+		var err error
+		c, err = cassette.Load(path)
+		if errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("no cassette recorded at %s, record it with make testacc-record", path)
+		}
+		require.NoError(t, err)
+	default:
+		t.Fatalf("unexpected TIMESCALE_CASSETTE %q, expected record or replay", mode)
+	}
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"timescale": providerserver.NewProtocol6WithError(&TimescaleProvider{
+			version:       "test",
+			clientOptions: []tsClient.Option{tsClient.WithTransport(c)},
+		}),
+	}
+}
+
 type VPCConfig struct {
 	ResourceName string
 	Name         string
@@ -228,3 +288,312 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("environment variable TIMESCALE_DEV_URL not set")
 	}
 }
+
+// newFakeClient returns an in-memory client to drive resources and data
+// sources without network access, and shortens the waits of
+// waitForServiceReadiness for the duration of the test.
+func newFakeClient(t *testing.T) *fake.Client {
+	t.Helper()
+	polling, grace := serviceReadinessPolling, serviceChangeGracePeriod
+	serviceReadinessPolling = tsClient.PollPolicy{MinInterval: time.Millisecond, MaxInterval: time.Millisecond}
+	serviceChangeGracePeriod = 200 * time.Millisecond
+	t.Cleanup(func() { serviceReadinessPolling, serviceChangeGracePeriod = polling, grace })
+	return fake.New("")
+}
+
+// emptyState returns a null state for a resource, data source or provider
+// schema.
+func emptyState(t *testing.T, s any) tfsdk.State {
+	t.Helper()
+	ctx := context.Background()
+	switch s := s.(type) {
+	case resourceschema.Schema:
+		return tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	case datasourceschema.Schema:
+		return tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	case providerschema.Schema:
+		return tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	}
+	t.Fatalf("unexpected schema type %T", s)
+	return tfsdk.State{}
+}
+
+// tfValue converts a model to the terraform value of the given schema, e.g.
+// to build a plan, a prior state or a config.
+func tfValue(t *testing.T, s any, model any) tftypes.Value {
+	t.Helper()
+	state := emptyState(t, s)
+	requireNoErrors(t, state.Set(context.Background(), model))
+	return state.Raw
+}
+
+func requireNoErrors(t *testing.T, diags diag.Diagnostics) {
+	t.Helper()
+	require.False(t, diags.HasError(), "%v", diags)
+}
+
+// configureProvider configures a provider with the given model and returns
+// its client.
+func configureProvider(t *testing.T, model TimescaleProviderModel) (*tsClient.Client, diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+	p := &TimescaleProvider{version: "test"}
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	config := tfsdk.Config{Schema: schemaResp.Schema, Raw: tfValue(t, schemaResp.Schema, model)}
+	var resp provider.ConfigureResponse
+	p.Configure(ctx, provider.ConfigureRequest{Config: config}, &resp)
+	client, _ := resp.ResourceData.(*tsClient.Client)
+	return client, resp.Diagnostics
+}
+
+// newProviderModel returns a provider configuration with client credentials
+// and every optional setting unset.
+func newProviderModel() TimescaleProviderModel {
+	return TimescaleProviderModel{
+		ProjectID:             types.StringValue("project"),
+		AccessToken:           types.StringNull(),
+		AccessKey:             types.StringValue("access"),
+		SecretKey:             types.StringValue("secret"),
+		CredentialProcess:     types.StringNull(),
+		Profile:               types.StringNull(),
+		SharedCredentialsFile: types.StringNull(),
+		APIURL:                types.StringNull(),
+		AuthURL:               types.StringNull(),
+		RetryMaxAttempts:      types.Int64Null(),
+		RetryMaxWait:          types.StringNull(),
+		LogHTTPBodies:         types.BoolNull(),
+		CacheTTL:              types.StringNull(),
+		MaxConcurrentRequests: types.Int64Null(),
+		RequestsPerSecond:     types.Float64Null(),
+		ProxyURL:              types.StringNull(),
+		CABundle:              types.StringNull(),
+		ClientCertificate:     types.StringNull(),
+		ClientKey:             types.StringNull(),
+		RequestTimeout:        types.StringNull(),
+		KeepAlive:             types.StringNull(),
+	}
+}
+
+func TestProvider_Configure_APIURL(t *testing.T) {
+	staging, stagingServer := testserver.Start(testserver.Options{})
+	t.Cleanup(stagingServer.Close)
+	production, productionServer := testserver.Start(testserver.Options{})
+	t.Cleanup(productionServer.Close)
+	t.Setenv("TIMESCALE_DEV_URL", productionServer.URL)
+
+	// Aliased providers each talk to their own API.
+	model := newProviderModel()
+	model.APIURL = types.StringValue(stagingServer.URL)
+	stagingClient, diags := configureProvider(t, model)
+	requireNoErrors(t, diags)
+	productionClient, diags := configureProvider(t, newProviderModel())
+	requireNoErrors(t, diags)
+
+	_, err := stagingClient.GetProducts(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, staging.Requests("GetProducts"))
+	require.Equal(t, 0, production.Requests("GetProducts"))
+	_, err = productionClient.GetProducts(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, production.Requests("GetProducts"))
+
+	// The credentials exchange can be served separately.
+	model.AuthURL = types.StringValue(productionServer.URL)
+	_, diags = configureProvider(t, model)
+	requireNoErrors(t, diags)
+	require.Equal(t, 1, staging.Requests("GetJWTForClientCredentials"))
+	require.Equal(t, 2, production.Requests("GetJWTForClientCredentials"))
+}
+
+func TestProvider_Configure_InvalidURL(t *testing.T) {
+	t.Setenv("TIMESCALE_DEV_URL", "console.example.com")
+	_, diags := configureProvider(t, newProviderModel())
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Detail(), "TIMESCALE_DEV_URL")
+
+	require.NoError(t, validateURL("https://console.example.com/api/query"))
+	require.Error(t, validateURL("console.example.com"))
+	require.Error(t, validateURL("ftp://console.example.com"))
+}
+
+func TestProvider_Configure_Environment(t *testing.T) {
+	s, server := testserver.Start(testserver.Options{AccessKey: "env-access", SecretKey: "env-secret"})
+	t.Cleanup(server.Close)
+	t.Setenv("TIMESCALE_DEV_URL", server.URL)
+
+	block := newProviderModel()
+	empty := newProviderModel()
+	empty.ProjectID, empty.AccessKey, empty.SecretKey = types.StringNull(), types.StringNull(), types.StringNull()
+	withToken := empty
+	withToken.ProjectID = types.StringValue("project")
+	withToken.AccessToken = types.StringValue("token")
+
+	tests := map[string]struct {
+		model TimescaleProviderModel
+		env   map[string]string
+		// err is a part of the expected error detail, if any.
+		err string
+	}{
+		"empty block": {
+			model: empty,
+			env: map[string]string{
+				"TIMESCALE_PROJECT_ID": "project",
+				"TIMESCALE_ACCESS_KEY": "env-access",
+				"TIMESCALE_SECRET_KEY": "env-secret",
+			},
+		},
+		"block takes precedence": {
+			model: block,
+			env:   map[string]string{"TIMESCALE_PROJECT_ID": "other"},
+			err:   "invalid client credentials",
+		},
+		"secret key from the environment": {
+			model: func() TimescaleProviderModel {
+				m := empty
+				m.ProjectID, m.AccessKey = types.StringValue("project"), types.StringValue("env-access")
+				return m
+			}(),
+			env: map[string]string{"TIMESCALE_SECRET_KEY": "env-secret"},
+		},
+		"token in block ignores environment keys": {
+			model: withToken,
+			env:   map[string]string{"TIMESCALE_ACCESS_KEY": "env-access", "TIMESCALE_SECRET_KEY": "env-secret"},
+		},
+		"missing project": {
+			model: empty,
+			env:   map[string]string{"TIMESCALE_ACCESS_TOKEN": "token"},
+			err:   "TIMESCALE_PROJECT_ID",
+		},
+		"missing secret key": {
+			model: empty,
+			env:   map[string]string{"TIMESCALE_PROJECT_ID": "project", "TIMESCALE_ACCESS_KEY": "env-access"},
+			err:   "The access key is set by the TIMESCALE_ACCESS_KEY environment variable but the secret key is missing",
+		},
+		"missing credentials": {
+			model: empty,
+			env:   map[string]string{"TIMESCALE_PROJECT_ID": "project"},
+			err:   "No credentials are configured",
+		},
+		"conflicting environment": {
+			model: empty,
+			env: map[string]string{
+				"TIMESCALE_PROJECT_ID":   "project",
+				"TIMESCALE_ACCESS_TOKEN": "token",
+				"TIMESCALE_ACCESS_KEY":   "env-access",
+			},
+			err: "an access token by the TIMESCALE_ACCESS_TOKEN environment variable and client credentials by the TIMESCALE_ACCESS_KEY environment variable are set",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, k := range []string{"TIMESCALE_PROJECT_ID", "TIMESCALE_ACCESS_KEY", "TIMESCALE_SECRET_KEY", "TIMESCALE_ACCESS_TOKEN", "TIMESCALE_CREDENTIAL_PROCESS", "TIMESCALE_PROFILE"} {
+				t.Setenv(k, test.env[k])
+			}
+			t.Setenv("TIMESCALE_SHARED_CREDENTIALS_FILE", "")
+			t.Setenv("HOME", t.TempDir())
+			exchanges := s.Requests("GetJWTForClientCredentials")
+			client, diags := configureProvider(t, test.model)
+			if test.err != "" {
+				require.True(t, diags.HasError())
+				require.Contains(t, diags[0].Detail(), test.err)
+				return
+			}
+			requireNoErrors(t, diags)
+			require.NotNil(t, client)
+			if test.model.AccessToken.IsNull() {
+				require.Equal(t, exchanges+1, s.Requests("GetJWTForClientCredentials"))
+			}
+		})
+	}
+}
+
+func TestProvider_Configure_CredentialProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands need a POSIX shell")
+	}
+	s, server := testserver.Start(testserver.Options{AccessKey: "process-access", SecretKey: "process-secret"})
+	t.Cleanup(server.Close)
+	t.Setenv("TIMESCALE_DEV_URL", server.URL)
+	t.Setenv("TIMESCALE_ACCESS_KEY", "env-access")
+	t.Setenv("TIMESCALE_SECRET_KEY", "env-secret")
+
+	// The credential process of the provider block wins over the environment.
+	model := newProviderModel()
+	model.AccessKey, model.SecretKey = types.StringNull(), types.StringNull()
+	model.CredentialProcess = types.StringValue(`echo '{"access_key":"process-access","secret_key":"process-secret"}'`)
+	_, diags := configureProvider(t, model)
+	requireNoErrors(t, diags)
+	require.Equal(t, 1, s.Requests("GetJWTForClientCredentials"))
+
+	model.CredentialProcess = types.StringValue(`echo 'locked' >&2; exit 1`)
+	_, diags = configureProvider(t, model)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Detail(), `credential process set by the "credential_process" attribute`)
+	require.Contains(t, diags[0].Detail(), "locked")
+}
+
+func TestProvider_Configure_CacheTTL(t *testing.T) {
+	s, server := testserver.Start(testserver.Options{})
+	t.Cleanup(server.Close)
+	t.Setenv("TIMESCALE_DEV_URL", server.URL)
+	ctx := context.Background()
+
+	// The cache is on by default, and shared by the resources and data
+	// sources until the project changes.
+	client, diags := configureProvider(t, newProviderModel())
+	requireNoErrors(t, diags)
+	for i := 0; i < 3; i++ {
+		_, err := client.GetVPCs(ctx)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, s.Requests("GetAllVPCs"))
+	_, err := client.CreateVPC(ctx, "vpc", "10.0.0.0/24", "us-east-1")
+	require.NoError(t, err)
+	_, err = client.GetVPCByName(ctx, "vpc")
+	require.NoError(t, err)
+	require.Equal(t, 2, s.Requests("GetAllVPCs"))
+
+	model := newProviderModel()
+	model.CacheTTL = types.StringValue("0s")
+	client, diags = configureProvider(t, model)
+	requireNoErrors(t, diags)
+	for i := 0; i < 2; i++ {
+		_, err := client.GetVPCs(ctx)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 4, s.Requests("GetAllVPCs"))
+
+	model.CacheTTL = types.StringValue("soon")
+	_, diags = configureProvider(t, model)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Detail(), "cache_ttl")
+}
+
+func TestProvider_Configure_Transport(t *testing.T) {
+	server := httptest.NewTLSServer(testserver.New(testserver.Options{}))
+	t.Cleanup(server.Close)
+	t.Setenv("TIMESCALE_DEV_URL", server.URL)
+	ca := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte(ca), 0o600))
+
+	// The certificate of the server is only trusted through the CA bundle.
+	_, diags := configureProvider(t, newProviderModel())
+	require.True(t, diags.HasError())
+	for _, bundle := range []string{ca, caFile} {
+		model := newProviderModel()
+		model.CABundle = types.StringValue(bundle)
+		model.RequestTimeout = types.StringValue("10s")
+		model.KeepAlive = types.StringValue("0s")
+		_, diags = configureProvider(t, model)
+		requireNoErrors(t, diags)
+	}
+
+	model := newProviderModel()
+	model.CABundle = types.StringValue(filepath.Join(t.TempDir(), "missing.pem"))
+	_, diags = configureProvider(t, model)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Detail(), "ca_bundle must hold PEM data or the path of a file")
+}
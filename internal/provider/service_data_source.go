@@ -12,7 +12,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
-	tsClient "github.com/timescale/terraform-provider-timescale/internal/client"
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -25,18 +25,20 @@ func NewServiceDataSource() datasource.DataSource {
 
 // ServiceDataSource defines the data source implementation.
 type ServiceDataSource struct {
-	client *tsClient.Client
+	client tsClient.API
 }
 
 // ServiceDataSourceModel describes the data source data model.
 type ServiceDataSourceModel struct {
 	ID         types.String    `tfsdk:"id"`
+	ProjectID  types.String    `tfsdk:"project_id"`
 	Name       types.String    `tfsdk:"name"`
 	RegionCode types.String    `tfsdk:"region_code"`
 	Spec       SpecModel       `tfsdk:"spec"`
 	Resources  []ResourceModel `tfsdk:"resources"`
 	Created    types.String    `tfsdk:"created"`
 	VpcID      types.Int64     `tfsdk:"vpc_id"`
+	ForkedFrom types.Object    `tfsdk:"forked_from"`
 }
 
 type SpecModel struct {
@@ -73,6 +75,12 @@ func (d *ServiceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 				Description:         "service id",
 				Required:            true,
 			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the project of the service. Defaults to the project of the provider.",
+				Description:         "project id",
+				Optional:            true,
+				Computed:            true,
+			},
 			"name": schema.StringAttribute{
 				MarkdownDescription: "Service Name is the configurable name assigned to this resource. If none is provided, a default will be generated by the provider.",
 				Description:         "service name",
@@ -153,6 +161,25 @@ func (d *ServiceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 				Optional:            true,
 				Computed:            true,
 			},
+			"forked_from": schema.SingleNestedAttribute{
+				MarkdownDescription: "The service this service was forked from, if any. Read replicas are forks with `is_standby` set.",
+				Description:         "The service this service was forked from, if any.",
+				Computed:            true,
+				Attributes: map[string]schema.Attribute{
+					"service_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the source service.",
+						Computed:            true,
+					},
+					"project_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the project of the source service.",
+						Computed:            true,
+					},
+					"is_standby": schema.BoolAttribute{
+						MarkdownDescription: "Whether the service is a read replica of the source service.",
+						Computed:            true,
+					},
+				},
+			},
 		},
 	}
 }
@@ -163,12 +190,12 @@ func (d *ServiceDataSource) Configure(ctx context.Context, req datasource.Config
 	if req.ProviderData == nil {
 		return
 	}
-	client, ok := req.ProviderData.(*tsClient.Client)
+	client, ok := req.ProviderData.(tsClient.API)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Client Type",
-			fmt.Sprintf("Expected *tsClient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected tsClient.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -177,10 +204,14 @@ func (d *ServiceDataSource) Configure(ctx context.Context, req datasource.Config
 }
 
 func (d *ServiceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := startSpan(ctx, "ServiceDataSource.Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
 	tflog.Trace(ctx, "ServiceDataSource.Read")
 
 	var id string
+	var projectID types.String
 	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("project_id"), &projectID)...)
 
 	if resp.Diagnostics.HasError() {
 		tflog.Error(ctx, fmt.Sprintf("error reading terraform plan %v", resp.Diagnostics.Errors()))
@@ -188,12 +219,12 @@ func (d *ServiceDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	}
 
 	tflog.Info(ctx, "Getting Service: "+id)
-	service, err := d.client.GetService(ctx, id)
+	service, err := projectClient(d.client, projectID).GetService(ctx, id)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read service, got error: %s", err))
 		return
 	}
-	state := serviceToDataModel(resp.Diagnostics, service)
+	state := serviceToDataModel(&resp.Diagnostics, service)
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 	if resp.Diagnostics.HasError() {
 		tflog.Error(ctx, fmt.Sprintf("error updating terraform state %v", resp.Diagnostics.Errors()))
@@ -201,23 +232,25 @@ func (d *ServiceDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	}
 }
 
-func serviceToDataModel(diag diag.Diagnostics, s *tsClient.Service) ServiceDataSourceModel {
+func serviceToDataModel(diags *diag.Diagnostics, s *tsClient.Service) ServiceDataSourceModel {
 	serviceModel := ServiceDataSourceModel{
 		ID:         types.StringValue(s.ID),
+		ProjectID:  types.StringValue(s.ProjectID),
 		Name:       types.StringValue(s.Name),
 		RegionCode: types.StringValue(s.RegionCode),
 		Spec: SpecModel{
-			Hostname:       types.StringValue(s.ServiceSpec.Hostname),
-			Username:       types.StringValue(s.ServiceSpec.Username),
-			Port:           types.Int64Value(s.ServiceSpec.Port),
-			PoolerHostname: types.StringValue(s.ServiceSpec.PoolerHostname),
-			PoolerPort:     types.Int64Value(s.ServiceSpec.PoolerPort),
+			Hostname:       types.StringValue(s.Spec.Hostname),
+			Username:       types.StringValue(s.Spec.Username),
+			Port:           types.Int64Value(s.Spec.Port),
+			PoolerHostname: types.StringValue(s.Spec.PoolerHostName),
+			PoolerPort:     types.Int64Value(s.Spec.PoolerPort),
 		},
-		Created: types.StringValue(s.Created),
+		Created:    types.StringValue(s.Created),
+		ForkedFrom: forkedFromValue(diags, s),
 	}
 	if s.VPCEndpoint != nil {
-		if vpcID, err := strconv.ParseInt(s.VPCEndpoint.VPCId, 10, 64); err != nil {
-			diag.AddError("Parse Error", "could not parse vpcID")
+		if vpcID, err := strconv.ParseInt(s.VPCEndpoint.VPCID, 10, 64); err != nil {
+			diags.AddError("Parse Error", "could not parse vpcID")
 		} else {
 			serviceModel.VpcID = types.Int64Value(vpcID)
 		}
@@ -1,9 +1,16 @@
 package provider
 
 import (
+	"context"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
 )
 
 func TestServiceDataSource(t *testing.T) {
@@ -40,3 +47,83 @@ func newServiceDataSource() string {
 				}
 `
 }
+
+func TestServiceDataSource_Fake_Read(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+	created, err := client.CreateService(ctx, tsClient.CreateServiceRequest{Name: "data source", MilliCPU: "1000", MemoryGB: "4", ReplicaCount: "1"})
+	require.NoError(t, err)
+
+	d := &ServiceDataSource{client: client}
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+
+	config := emptyState(t, s)
+	requireNoErrors(t, config.SetAttribute(ctx, path.Root("id"), created.Service.ID))
+	resp := datasource.ReadResponse{State: emptyState(t, s)}
+	d.Read(ctx, datasource.ReadRequest{Config: tfsdk.Config{Schema: s, Raw: config.Raw}}, &resp)
+	requireNoErrors(t, resp.Diagnostics)
+
+	var model ServiceDataSourceModel
+	requireNoErrors(t, resp.State.Get(ctx, &model))
+	require.Equal(t, "data source", model.Name.ValueString())
+	require.Len(t, model.Resources, 1)
+	require.EqualValues(t, 1000, model.Resources[0].Spec.MilliCPU.ValueInt64())
+	require.True(t, model.Resources[0].Spec.EnableHAReplica.ValueBool())
+	require.True(t, model.ForkedFrom.IsNull())
+}
+
+func TestServiceDataSource_Fake_Fork(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+	source, err := client.CreateService(ctx, tsClient.CreateServiceRequest{Name: "prod", MilliCPU: "500", MemoryGB: "2"})
+	require.NoError(t, err)
+	fork, err := client.Project("other").CreateService(ctx, tsClient.CreateServiceRequest{
+		Name: "staging", MilliCPU: "500", MemoryGB: "2",
+		ForkConfig: &tsClient.ForkConfig{ProjectID: source.Service.ProjectID, ServiceID: source.Service.ID, ForkStrategy: tsClient.ForkStrategyNow},
+	})
+	require.NoError(t, err)
+
+	d := &ServiceDataSource{client: client}
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+
+	config := emptyState(t, s)
+	requireNoErrors(t, config.SetAttribute(ctx, path.Root("id"), fork.Service.ID))
+	requireNoErrors(t, config.SetAttribute(ctx, path.Root("project_id"), "other"))
+	resp := datasource.ReadResponse{State: emptyState(t, s)}
+	d.Read(ctx, datasource.ReadRequest{Config: tfsdk.Config{Schema: s, Raw: config.Raw}}, &resp)
+	requireNoErrors(t, resp.Diagnostics)
+
+	var lineage forkedFromModel
+	requireNoErrors(t, resp.State.GetAttribute(ctx, path.Root("forked_from"), &lineage))
+	require.Equal(t, source.Service.ID, lineage.ServiceID.ValueString())
+	require.Equal(t, source.Service.ProjectID, lineage.ProjectID.ValueString())
+	require.False(t, lineage.IsStandby.ValueBool())
+}
+
+func TestServiceDataSource_Fake_OtherProject(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+	created, err := client.Project("other").CreateService(ctx, tsClient.CreateServiceRequest{Name: "elsewhere", MilliCPU: "500", MemoryGB: "2"})
+	require.NoError(t, err)
+
+	d := &ServiceDataSource{client: client}
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+
+	config := emptyState(t, s)
+	requireNoErrors(t, config.SetAttribute(ctx, path.Root("id"), created.Service.ID))
+	requireNoErrors(t, config.SetAttribute(ctx, path.Root("project_id"), "other"))
+	resp := datasource.ReadResponse{State: emptyState(t, s)}
+	d.Read(ctx, datasource.ReadRequest{Config: tfsdk.Config{Schema: s, Raw: config.Raw}}, &resp)
+	requireNoErrors(t, resp.Diagnostics)
+
+	var model ServiceDataSourceModel
+	requireNoErrors(t, resp.State.Get(ctx, &model))
+	require.Equal(t, "elsewhere", model.Name.ValueString())
+	require.Equal(t, "other", model.ProjectID.ValueString())
+}
@@ -0,0 +1,234 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
+)
+
+var forkStrategies = []string{tsClient.ForkStrategyLastSnapshot, tsClient.ForkStrategyNow, tsClient.ForkStrategyPITR}
+
+// serviceForkFromModel maps the fork_from block of the service resource.
+type serviceForkFromModel struct {
+	ServiceID  types.String `tfsdk:"service_id"`
+	ProjectID  types.String `tfsdk:"project_id"`
+	Strategy   types.String `tfsdk:"strategy"`
+	TargetTime types.String `tfsdk:"target_time"`
+}
+
+// forkedFromModel maps the lineage of a service, as reported by the API.
+type forkedFromModel struct {
+	ServiceID types.String `tfsdk:"service_id"`
+	ProjectID types.String `tfsdk:"project_id"`
+	IsStandby types.Bool   `tfsdk:"is_standby"`
+}
+
+var forkedFromAttrTypes = map[string]attr.Type{
+	"service_id": types.StringType,
+	"project_id": types.StringType,
+	"is_standby": types.BoolType,
+}
+
+// forkedFromValue returns the lineage of s, null when it is not a fork.
+func forkedFromValue(diags *diag.Diagnostics, s *tsClient.Service) types.Object {
+	if s.ForkedFromID == nil {
+		return types.ObjectNull(forkedFromAttrTypes)
+	}
+	value, d := types.ObjectValueFrom(context.Background(), forkedFromAttrTypes, forkedFromModel{
+		ServiceID: types.StringValue(s.ForkedFromID.ServiceID),
+		ProjectID: types.StringValue(s.ForkedFromID.ProjectID),
+		IsStandby: types.BoolValue(s.ForkedFromID.IsStandby),
+	})
+	diags.Append(d...)
+	return value
+}
+
+// forkConfig returns the fork configuration creating a service from source.
+func (m *serviceForkFromModel) forkConfig(source *tsClient.Service) *tsClient.ForkConfig {
+	strategy := m.Strategy.ValueString()
+	if strategy == "" {
+		strategy = tsClient.ForkStrategyLastSnapshot
+	}
+	return &tsClient.ForkConfig{
+		ProjectID:    source.ProjectID,
+		ServiceID:    source.ID,
+		ForkStrategy: strategy,
+		TargetTime:   m.TargetTime.ValueString(),
+	}
+}
+
+// errRecoveryWindowUnavailable is returned by validateRecoveryTarget when the
+// API does not serve recovery windows. The API then validates the target
+// time itself.
+var errRecoveryWindowUnavailable = errors.New("the recovery window is unavailable")
+
+// validateRecoveryTarget returns an error if service id cannot be restored to
+// targetTime.
+func validateRecoveryTarget(ctx context.Context, client tsClient.API, id, targetTime string) error {
+	target, err := time.Parse(time.RFC3339, targetTime)
+	if err != nil {
+		return fmt.Errorf("%q is not an RFC 3339 time", targetTime)
+	}
+	window, err := client.GetRecoveryWindow(ctx, id)
+	if tsClient.IsUnsupported(err) {
+		return fmt.Errorf("%w: %w", errRecoveryWindowUnavailable, err)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get the recovery window of service %s, got error: %w", id, err)
+	}
+	ok, err := window.Contains(target)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("service %s can only be restored to a time between %s and %s, got %s", id, window.Earliest, window.Latest, targetTime)
+	}
+	return nil
+}
+
+// serviceForkFromBlock is the fork_from block of the service resource.
+func serviceForkFromBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Creates the service as a fork of another service. Changing it creates a new service, removing it keeps the service. Conflicts with `read_replica_source`.",
+		Description:         "Creates the service as a fork of another service. Changing it creates a new service, removing it keeps the service.",
+		Attributes: map[string]schema.Attribute{
+			"service_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the service to fork.",
+				Description:         "ID of the service to fork.",
+				Required:            true,
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the project of the service to fork. Defaults to the `project_id` of this service.",
+				Description:         "ID of the project of the service to fork.",
+				Optional:            true,
+			},
+			"strategy": schema.StringAttribute{
+				MarkdownDescription: "Data the fork starts with: `LAST_SNAPSHOT` for the last snapshot of the service, `NOW` for a snapshot taken when the fork is created, or `PITR` for the service as it was at `target_time`. Defaults to `LAST_SNAPSHOT`.",
+				Description:         "Data the fork starts with: LAST_SNAPSHOT, NOW or PITR. Defaults to LAST_SNAPSHOT.",
+				Optional:            true,
+				Validators:          []validator.String{stringvalidator.OneOf(forkStrategies...)},
+			},
+			"target_time": schema.StringAttribute{
+				MarkdownDescription: "Time the service is forked at with the `PITR` strategy, in RFC 3339 format, e.g. `2024-05-01T12:00:00Z`. It must be within the recovery window of the service, which is checked before the fork is created when the API reports it.",
+				Description:         "Time the service is forked at with the PITR strategy, in RFC 3339 format.",
+				Optional:            true,
+			},
+		},
+		Validators: []validator.Object{
+			objectvalidator.ConflictsWith(path.MatchRoot("read_replica_source")),
+			forkFromValidator{},
+		},
+		PlanModifiers: []planmodifier.Object{
+			objectplanmodifier.RequiresReplaceIf(requiresForkReplace,
+				"Changing the fork of a service creates a new service.",
+				"Changing the fork of a service creates a new service."),
+		},
+	}
+}
+
+// forkedFromAttribute is the read-only lineage of the service resource.
+func forkedFromAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "The service this service was forked from, if any. Read replicas are forks with `is_standby` set.",
+		Description:         "The service this service was forked from, if any.",
+		Computed:            true,
+		Attributes: map[string]schema.Attribute{
+			"service_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the source service.",
+				Computed:            true,
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the project of the source service.",
+				Computed:            true,
+			},
+			"is_standby": schema.BoolAttribute{
+				MarkdownDescription: "Whether the service is a read replica of the source service.",
+				Computed:            true,
+			},
+		},
+		PlanModifiers: []planmodifier.Object{
+			objectplanmodifier.UseStateForUnknown(),
+		},
+	}
+}
+
+// requiresForkReplace replaces services whose fork_from changes, unless the
+// block is removed, since it is only used on creation, or added to a service
+// that already is a fork of the same service, e.g. after an import.
+func requiresForkReplace(ctx context.Context, req planmodifier.ObjectRequest, resp *objectplanmodifier.RequiresReplaceIfFuncResponse) {
+	if req.PlanValue.IsNull() {
+		return
+	}
+	resp.RequiresReplace = true
+	if !req.StateValue.IsNull() {
+		return
+	}
+	var lineage types.Object
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("forked_from"), &lineage)...)
+	if lineage.IsNull() || lineage.IsUnknown() {
+		return
+	}
+	var source forkedFromModel
+	var fork serviceForkFromModel
+	resp.Diagnostics.Append(lineage.As(ctx, &source, basetypes.ObjectAsOptions{})...)
+	resp.Diagnostics.Append(req.PlanValue.As(ctx, &fork, basetypes.ObjectAsOptions{})...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.RequiresReplace = !fork.ServiceID.Equal(source.ServiceID)
+}
+
+// forkFromValidator checks that target_time is set with the PITR strategy
+// only.
+type forkFromValidator struct{}
+
+var _ validator.Object = forkFromValidator{}
+
+func (v forkFromValidator) Description(_ context.Context) string {
+	return "target_time must be set with the PITR strategy, and only with it"
+}
+
+func (v forkFromValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v forkFromValidator) ValidateObject(ctx context.Context, req validator.ObjectRequest, resp *validator.ObjectResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	var fork serviceForkFromModel
+	resp.Diagnostics.Append(req.ConfigValue.As(ctx, &fork, basetypes.ObjectAsOptions{})...)
+	if resp.Diagnostics.HasError() || fork.Strategy.IsUnknown() || fork.TargetTime.IsUnknown() {
+		return
+	}
+	targetTimePath := req.Path.AtName("target_time")
+	pitr := fork.Strategy.ValueString() == tsClient.ForkStrategyPITR
+	switch {
+	case pitr && fork.TargetTime.IsNull():
+		resp.Diagnostics.AddAttributeError(targetTimePath, ErrInvalidAttribute,
+			fmt.Sprintf("target_time is required with the %s strategy.", tsClient.ForkStrategyPITR))
+	case !pitr && !fork.TargetTime.IsNull():
+		resp.Diagnostics.AddAttributeError(targetTimePath, ErrInvalidAttribute,
+			fmt.Sprintf("target_time is only supported by the %s strategy.", tsClient.ForkStrategyPITR))
+	case pitr:
+		if _, err := time.Parse(time.RFC3339, fork.TargetTime.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(targetTimePath, ErrInvalidAttribute,
+				fmt.Sprintf("%q is not an RFC 3339 time, such as %q.", fork.TargetTime.ValueString(), "2024-05-01T12:00:00Z"))
+		}
+	}
+}
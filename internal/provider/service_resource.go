@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,9 +24,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"go.opentelemetry.io/otel/attribute"
 
-	tsClient "github.com/timescale/terraform-provider-timescale/internal/client"
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
 	multiplyvalidator "github.com/timescale/terraform-provider-timescale/internal/utils"
 )
 
@@ -50,6 +51,15 @@ const (
 var (
 	memorySizes   = []int64{2, 4, 8, 16, 32, 64, 128}
 	milliCPUSizes = []int64{500, 1000, 2000, 4000, 8000, 16000, 32000}
+
+	// serviceReadinessPolling paces waitForServiceReadiness when the API
+	// cannot push status changes. Unit tests shorten it.
+	serviceReadinessPolling = tsClient.DefaultPollPolicy()
+
+	// serviceChangeGracePeriod is how long a service that still reports READY
+	// after a change is not trusted to have applied it, since the API takes
+	// a moment to move it to a pending status. Unit tests shorten it.
+	serviceChangeGracePeriod = 30 * time.Second
 )
 
 func NewServiceResource() resource.Resource {
@@ -61,12 +71,13 @@ var readReplicaMu sync.Mutex
 
 // ServiceResource defines the resource implementation.
 type ServiceResource struct {
-	client *tsClient.Client
+	client tsClient.API
 }
 
 // serviceResourceModel maps the resource schema data.
 type serviceResourceModel struct {
 	ID                types.String   `tfsdk:"id"`
+	ProjectID         types.String   `tfsdk:"project_id"`
 	Name              types.String   `tfsdk:"name"`
 	Timeouts          timeouts.Value `tfsdk:"timeouts"`
 	MilliCPU          types.Int64    `tfsdk:"milli_cpu"`
@@ -82,6 +93,10 @@ type serviceResourceModel struct {
 	EnableHAReplica   types.Bool     `tfsdk:"enable_ha_replica"`
 	ReadReplicaSource types.String   `tfsdk:"read_replica_source"`
 	VpcID             types.Int64    `tfsdk:"vpc_id"`
+	// ForkFrom is the configured fork, ForkedFrom the lineage reported by
+	// the API.
+	ForkFrom   *serviceForkFromModel `tfsdk:"fork_from"`
+	ForkedFrom types.Object          `tfsdk:"forked_from"`
 
 	ConnectionPoolerEnabled types.Bool `tfsdk:"connection_pooler_enabled"`
 }
@@ -109,6 +124,16 @@ The change has been taken into account but must still be propagated. You can run
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the project of this service. Defaults to the project of the provider. Changing it creates a new service.",
+				Description:         "project id",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"name": schema.StringAttribute{
 				MarkdownDescription: "Service Name is the configurable name assigned to this resource. If none is provided, a default will be generated by the provider.",
 				Description:         "service name",
@@ -225,6 +250,10 @@ The change has been taken into account but must still be propagated. You can run
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
+			"forked_from": forkedFromAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"fork_from": serviceForkFromBlock(),
 		},
 	}
 }
@@ -236,12 +265,12 @@ func (r *ServiceResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*tsClient.Client)
+	client, ok := req.ProviderData.(tsClient.API)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *tsClient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected tsClient.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -251,6 +280,8 @@ func (r *ServiceResource) Configure(ctx context.Context, req resource.ConfigureR
 }
 
 func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startSpan(ctx, "ServiceResource.Create")
+	defer func() { endSpan(span, resp.Diagnostics) }()
 	tflog.Trace(ctx, "ServiceResource.Create")
 	var plan serviceResourceModel
 
@@ -260,6 +291,7 @@ func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	client := projectClient(r.client, plan.ProjectID)
 	// Enabling HA replica means one replica
 	var replicaCount int64
 	if plan.EnableHAReplica.ValueBool() {
@@ -284,12 +316,12 @@ func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest
 		readReplicaMu.Lock()
 		defer readReplicaMu.Unlock()
 
-		primary, err := r.client.GetService(ctx, readReplicaSource)
+		primary, err := client.GetService(ctx, readReplicaSource)
 		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("unable to get primary service %s, got error: %s", readReplicaSource, err))
+			resp.Diagnostics.AddError(clientErrorSummary(err), fmt.Sprintf("unable to get primary service %s, got error: %s", readReplicaSource, err))
 			return
 		}
-		err = r.validateCreateReadReplicaRequest(ctx, primary, plan)
+		err = validateCreateReadReplicaRequest(ctx, client, primary, plan)
 		if err != nil {
 			resp.Diagnostics.AddError("read replica validation error", err.Error())
 			return
@@ -309,26 +341,64 @@ func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest
 			request.StorageGB = strconv.FormatInt(primary.Resources[0].Spec.StorageGB, 10)
 		}
 	}
+	if fork := plan.ForkFrom; fork != nil {
+		// Services are forked from their own project by default.
+		sourceProjectID := fork.ProjectID
+		if sourceProjectID.ValueString() == "" {
+			sourceProjectID = plan.ProjectID
+		}
+		sourceClient := projectClient(r.client, sourceProjectID)
+		source, err := sourceClient.GetService(ctx, fork.ServiceID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(clientErrorSummary(err), fmt.Sprintf("unable to get service %s to fork, got error: %s", fork.ServiceID.ValueString(), err))
+			return
+		}
+		if fork.Strategy.ValueString() == tsClient.ForkStrategyPITR {
+			err := validateRecoveryTarget(ctx, sourceClient, source.ID, fork.TargetTime.ValueString())
+			if errors.Is(err, errRecoveryWindowUnavailable) {
+				resp.Diagnostics.AddAttributeWarning(path.Root("fork_from").AtName("target_time"), "Recovery Window Unavailable",
+					fmt.Sprintf("The recovery window of service %s could not be read, so target_time is only validated by the Timescale API: %s", source.ID, err))
+			} else if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("fork_from").AtName("target_time"), "point-in-time restore validation error", err.Error())
+				return
+			}
+		}
+		if request.Name == "" {
+			request.Name = "fork-" + source.Name
+		}
+		if request.RegionCode == "" {
+			request.RegionCode = source.RegionCode
+		}
+		request.ForkConfig = fork.forkConfig(source)
+		if len(source.Resources) > 0 {
+			request.StorageGB = strconv.FormatInt(source.Resources[0].Spec.StorageGB, 10)
+		}
+	}
 
-	response, err := r.client.CreateService(ctx, request)
+	response, err := client.CreateService(ctx, request)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create service, got error: %s", err))
+		resp.Diagnostics.AddError(clientErrorSummary(err), fmt.Sprintf("Unable to create service, got error: %s", err))
 		return
 	}
 
+	if response.Adopted {
+		resp.Diagnostics.AddWarning("Service Adopted",
+			fmt.Sprintf("The request creating the service failed after reaching the Timescale API, and service %s was found to be created by it. "+
+				"Its initial password is unknown: reset it from the Timescale console to connect to the service.", response.Service.ID))
+	}
 	plan.Password = types.StringValue(response.InitialPassword)
-	service, err := r.waitForServiceReadiness(ctx, response.Service.ID, plan.Timeouts)
+	service, err := waitForServiceReadiness(ctx, client, response.Service.ID, plan.Timeouts, 0)
 	if err != nil {
 		resp.Diagnostics.AddError(ErrCreateTimeout, fmt.Sprintf("error occurred while waiting for service deployment, got error: %s", err))
 		// If we receive an error, attempt to delete the service to avoid having an orphaned instance.
-		_, err = r.client.DeleteService(context.Background(), response.Service.ID)
+		_, err = client.DeleteService(context.Background(), response.Service.ID)
 		if err != nil {
 			resp.Diagnostics.AddWarning("Error Deleting Resource", "error occurred attempting to delete the resource that timed out, please check your Timescale account to verify there is no unexpected service running from Terraform")
 		}
 		return
 	}
-	resourceModel := serviceToResource(resp.Diagnostics, service, plan)
+	resourceModel := serviceToResource(&resp.Diagnostics, service, plan)
 	resp.Diagnostics.Append(resp.State.Set(ctx, resourceModel)...)
 	if resp.Diagnostics.HasError() {
 		tflog.Error(ctx, fmt.Sprintf("error updating terraform state %v", resp.Diagnostics.Errors()))
@@ -336,64 +406,109 @@ func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest
 	}
 }
 
-func (r *ServiceResource) validateCreateReadReplicaRequest(ctx context.Context, primary *tsClient.Service, plan serviceResourceModel) error {
+func validateCreateReadReplicaRequest(ctx context.Context, client tsClient.API, primary *tsClient.Service, plan serviceResourceModel) error {
 	tflog.Trace(ctx, "validateCreateReadReplicaRequest")
 
-	if primary.ForkSpec != nil {
+	if primary.ForkedFromID != nil {
 		return errors.New(errReplicaFromFork)
 	}
 	if plan.EnableHAReplica.ValueBool() {
 		return errors.New(errReplicaWithHA)
 	}
-	services, err := r.client.GetAllServices(ctx)
+	services, err := client.GetAllServices(ctx)
 	if err != nil {
 		return err
 	}
 	for _, service := range services {
-		if service.ForkSpec != nil && service.ForkSpec.ServiceID == primary.ID {
+		if service.ForkedFromID != nil && service.ForkedFromID.ServiceID == primary.ID {
 			return errors.New(errMultipleReadReplicas)
 		}
 	}
 	return nil
 }
 
-func (r *ServiceResource) waitForServiceReadiness(ctx context.Context, id string, timeouts timeouts.Value) (*tsClient.Service, error) {
+// waitForServiceReadiness waits for a service to be READY. After a change,
+// grace is how long a READY status is ignored unless the service went
+// through a pending status, see serviceChangeGracePeriod.
+func waitForServiceReadiness(ctx context.Context, client tsClient.API, id string, timeouts timeouts.Value, grace time.Duration) (*tsClient.Service, error) {
+	ctx, span := startSpan(ctx, "ServiceResource.waitForServiceReadiness", attribute.String("timescale.service_id", id))
 	tflog.Trace(ctx, "ServiceResource.waitForServiceReadiness")
 
 	defaultTimeout := 45 * time.Minute
 	timeout, diags := timeouts.Create(ctx, defaultTimeout)
 	if diags != nil && diags.HasError() {
 		tflog.Error(ctx, fmt.Sprintf("found errs %v", diags.Errors()))
-		return nil, fmt.Errorf("unable to get timeout from config %v", diags.Errors())
-	}
-
-	conf := retry.StateChangeConf{
-		Pending:                   []string{"QUEUED", "CONFIGURING", "UNSTABLE"},
-		Target:                    []string{"READY"},
-		Delay:                     10 * time.Second,
-		Timeout:                   timeout,
-		PollInterval:              5 * time.Second,
-		ContinuousTargetOccurence: 1,
-		Refresh: func() (result interface{}, state string, err error) {
-			s, err := r.client.GetService(ctx, id)
-			if err != nil {
-				return nil, "", err
-			}
-			return s, s.Status, nil
-		},
-	}
-	result, err := conf.WaitForStateContext(ctx)
-	if err != nil {
+		err := fmt.Errorf("unable to get timeout from config %v", diags.Errors())
+		endSpanWithError(span, err)
 		return nil, err
 	}
-	s, ok := result.(*tsClient.Service)
-	if !ok {
-		return nil, fmt.Errorf("unexpected type found, expected Service but got %T", result)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	service, err := waitForStatus(ctx, tsClient.NewStatusWatcher(client, serviceReadinessPolling), id, timeout, grace)
+	endSpanWithError(span, err)
+	return service, err
+}
+
+// waitForStatus follows a service until it is READY, and fails if it ends in
+// any status but the pending ones. A READY status is only trusted once the
+// service went through a pending status or grace elapsed: until then, it can
+// be the state of the service from before a change. If the service is still
+// READY when grace elapses, it is read again.
+func waitForStatus(ctx context.Context, watcher *tsClient.StatusWatcher, id string, timeout time.Duration, grace time.Duration) (*tsClient.Service, error) {
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	updates := watcher.Watch(watchCtx, id)
+
+	trusted := grace <= 0
+	var graceElapsed <-chan time.Time
+	if !trusted {
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+		graceElapsed = timer.C
+	}
+	status := "unknown"
+	for updates != nil {
+		select {
+		case <-graceElapsed:
+			graceElapsed = nil
+			trusted = true
+			if status == "READY" {
+				cancel()
+				return waitForStatus(ctx, watcher, id, timeout, 0)
+			}
+		case update, ok := <-updates:
+			if !ok {
+				updates = nil
+				break
+			}
+			if update.Err != nil {
+				return nil, update.Err
+			}
+			status = update.Service.Status
+			tflog.Debug(ctx, "Service status: "+status)
+			switch status {
+			case "READY":
+				if trusted {
+					return update.Service, nil
+				}
+				tflog.Debug(ctx, "Ignoring the READY status, the change to the service may not be applied yet")
+			case "QUEUED", "CONFIGURING", "UNSTABLE":
+				trusted = true
+			default:
+				return nil, fmt.Errorf("unexpected state '%s', wanted target 'READY'", status)
+			}
+		}
+	}
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return nil, ctx.Err()
 	}
-	return s, nil
+	return nil, fmt.Errorf("timeout while waiting for state to become 'READY' (last state: '%s', timeout: %s)", status, timeout)
 }
 
 func (r *ServiceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startSpan(ctx, "ServiceResource.Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
 	tflog.Trace(ctx, "ServiceResource.Read")
 	var state serviceResourceModel
 	// Read Terraform prior state plan into the model
@@ -405,12 +520,19 @@ func (r *ServiceResource) Read(ctx context.Context, req resource.ReadRequest, re
 
 	tflog.Info(ctx, "Getting Service: "+state.ID.ValueString())
 
-	service, err := r.client.GetService(ctx, state.ID.ValueString())
+	service, err := projectClient(r.client, state.ProjectID).GetService(ctx, state.ID.ValueString())
+	if tsClient.IsNotFound(err) {
+		resp.Diagnostics.AddWarning("Service Not Found",
+			fmt.Sprintf("Service %s (%s) no longer exists and has been removed from the state, it will be recreated on the next apply.",
+				state.ID.ValueString(), state.Name.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read service, got error: %s", err))
+		resp.Diagnostics.AddError(clientErrorSummary(err), fmt.Sprintf("Unable to read service, got error: %s", err))
 		return
 	}
-	resourceModel := serviceToResource(resp.Diagnostics, service, state)
+	resourceModel := serviceToResource(&resp.Diagnostics, service, state)
 	// Save updated plan into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, resourceModel)...)
 	if resp.Diagnostics.HasError() {
@@ -420,6 +542,8 @@ func (r *ServiceResource) Read(ctx context.Context, req resource.ReadRequest, re
 }
 
 func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startSpan(ctx, "ServiceResource.Update")
+	defer func() { endSpan(span, resp.Diagnostics) }()
 	tflog.Trace(ctx, "ServiceResource.Update")
 	var plan, state serviceResourceModel
 	// Read Terraform plan data into the model
@@ -429,6 +553,7 @@ func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 	serviceID := state.ID.ValueString()
+	client := projectClient(r.client, state.ProjectID)
 
 	readReplicaSource := plan.ReadReplicaSource.ValueString()
 	if readReplicaSource != state.ReadReplicaSource.ValueString() {
@@ -460,12 +585,17 @@ func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	// reconfigured is set by the changes the service applies by going
+	// through pending statuses.
+	reconfigured := false
+
 	// Connection pooler ////////////////////////////////////////
 	if plan.ConnectionPoolerEnabled != state.ConnectionPoolerEnabled {
-		if err := r.client.ToggleConnectionPooler(ctx, serviceID, plan.ConnectionPoolerEnabled.ValueBool()); err != nil {
+		if err := client.ToggleConnectionPooler(ctx, serviceID, plan.ConnectionPoolerEnabled.ValueBool()); err != nil {
 			resp.Diagnostics.AddError("Failed to toggle connection pooler", err.Error())
 			return
 		}
+		reconfigured = true
 	}
 	if !plan.PoolerHostname.IsUnknown() {
 		resp.Diagnostics.AddError(ErrUpdateService, "Do not support pooler hostname change")
@@ -479,16 +609,17 @@ func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest
 	// HA Replica ////////////////////////////////////////
 	if plan.EnableHAReplica != state.EnableHAReplica {
 		if plan.EnableHAReplica.ValueBool() {
-			if err := r.client.SetReplicaCount(ctx, serviceID, 1); err != nil {
+			if err := client.SetReplicaCount(ctx, serviceID, 1); err != nil {
 				resp.Diagnostics.AddError("Failed to add a HA replica", err.Error())
 				return
 			}
 		} else {
-			if err := r.client.SetReplicaCount(ctx, serviceID, 0); err != nil {
+			if err := client.SetReplicaCount(ctx, serviceID, 0); err != nil {
 				resp.Diagnostics.AddError("Failed to remove a HA replica", err.Error())
 				return
 			}
 		}
+		reconfigured = true
 
 	}
 
@@ -496,22 +627,24 @@ func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest
 	if !plan.VpcID.Equal(state.VpcID) {
 		// if state.VpcId is known and different from plan.VpcId, we must detach first
 		if !state.VpcID.IsNull() && !state.VpcID.IsUnknown() {
-			if err := r.client.DetachServiceFromVPC(ctx, serviceID, state.VpcID.ValueInt64()); err != nil {
+			if err := client.DetachServiceFromVPC(ctx, serviceID, state.VpcID.ValueInt64()); err != nil {
 				resp.Diagnostics.AddError("Failed to detach service from VPC", err.Error())
 				return
 			}
+			reconfigured = true
 		}
 		// if plan.VpcId is known, it must be attached
 		if !plan.VpcID.IsNull() && !plan.VpcID.IsUnknown() {
-			if err := r.client.AttachServiceToVPC(ctx, serviceID, plan.VpcID.ValueInt64()); err != nil {
+			if err := client.AttachServiceToVPC(ctx, serviceID, plan.VpcID.ValueInt64()); err != nil {
 				resp.Diagnostics.AddError("Failed to attach service to VPC", err.Error())
 				return
 			}
+			reconfigured = true
 		}
 	}
 
 	if !plan.Name.Equal(state.Name) {
-		if err := r.client.RenameService(ctx, serviceID, plan.Name.ValueString()); err != nil {
+		if err := client.RenameService(ctx, serviceID, plan.Name.ValueString()); err != nil {
 			resp.Diagnostics.AddError("Failed to rename a service", err.Error())
 			return
 		}
@@ -532,19 +665,24 @@ func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest
 		}
 
 		if isResizeRequested {
-			if err := r.client.ResizeInstance(ctx, serviceID, resizeConfig); err != nil {
+			if err := client.ResizeInstance(ctx, serviceID, resizeConfig); err != nil {
 				resp.Diagnostics.AddError("Failed to resize an instance", err.Error())
 				return
 			}
+			reconfigured = true
 		}
 	}
 
-	service, err := r.waitForServiceReadiness(ctx, serviceID, plan.Timeouts)
+	var grace time.Duration
+	if reconfigured {
+		grace = serviceChangeGracePeriod
+	}
+	service, err := waitForServiceReadiness(ctx, client, serviceID, plan.Timeouts, grace)
 	if err != nil {
 		resp.Diagnostics.AddError(ErrCreateTimeout, fmt.Sprintf("error occurred while waiting for service reconfiguration, got error: %s", err))
 		return
 	}
-	resources := serviceToResource(resp.Diagnostics, service, plan)
+	resources := serviceToResource(&resp.Diagnostics, service, plan)
 	resp.Diagnostics.Append(resp.State.Set(ctx, resources)...)
 
 	if resp.Diagnostics.HasError() {
@@ -554,6 +692,8 @@ func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest
 }
 
 func (r *ServiceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startSpan(ctx, "ServiceResource.Delete")
+	defer func() { endSpan(span, resp.Diagnostics) }()
 	tflog.Trace(ctx, "ServiceResource.Delete")
 	var data serviceResourceModel
 
@@ -566,7 +706,11 @@ func (r *ServiceResource) Delete(ctx context.Context, req resource.DeleteRequest
 
 	tflog.Info(ctx, "Deleting Service: "+data.ID.ValueString())
 
-	_, err := r.client.DeleteService(ctx, data.ID.ValueString())
+	_, err := projectClient(r.client, data.ProjectID).DeleteService(ctx, data.ID.ValueString())
+	if tsClient.IsNotFound(err) {
+		tflog.Info(ctx, "Service already deleted: "+data.ID.ValueString())
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting Timescale Service",
@@ -576,41 +720,116 @@ func (r *ServiceResource) Delete(ctx context.Context, req resource.DeleteRequest
 	}
 }
 
+// ImportState imports a service by ID or by name, optionally in another
+// project than the provider's, see parseImportID.
 func (r *ServiceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	id, err := parseImportID(req.ID, importByID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID",
+			fmt.Sprintf("Unable to import service: %s. Import IDs are <service_id>, id:<service_id> or name:<name>, optionally prefixed by project:<project_id>/.", err))
+		return
+	}
+	serviceID := id.value
+	if id.key == importByName {
+		service, err := findServiceByName(ctx, projectClient(r.client, types.StringValue(id.projectID)), id.value)
+		if err != nil {
+			resp.Diagnostics.AddError(clientErrorSummary(err), fmt.Sprintf("Unable to import service: %s", err))
+			return
+		}
+		serviceID = service.ID
+	}
+	if id.projectID != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), id.projectID)...)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), serviceID)...)
+	resp.Diagnostics.AddWarning("Password Not Imported",
+		fmt.Sprintf("The password of service %s is only provided when the service is created, and cannot be imported: password stays empty in the state. "+
+			"Reset it from the Timescale console to connect to the service.", serviceID))
 }
 
-func serviceToResource(diag diag.Diagnostics, s *tsClient.Service, state serviceResourceModel) serviceResourceModel {
+// findServiceByName returns the only service of the project with the given
+// name.
+func findServiceByName(ctx context.Context, client tsClient.API, name string) (*tsClient.Service, error) {
+	services, err := client.GetAllServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var found []*tsClient.Service
+	for _, service := range services {
+		if service.Name == name {
+			found = append(found, service)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no service is named %q in project %s", name, client.ProjectID())
+	case 1:
+		return found[0], nil
+	}
+	ids := make([]string, len(found))
+	for i, service := range found {
+		ids[i] = service.ID
+	}
+	return nil, fmt.Errorf("%d services are named %q in project %s (%s), import one of them with id:<service_id>",
+		len(found), name, client.ProjectID(), strings.Join(ids, ", "))
+}
+
+// serviceToResource returns the state of service s. Only the password, the
+// timeouts and fork_from, which the API does not return, are taken from the
+// prior state, so that imported services are complete.
+func serviceToResource(diags *diag.Diagnostics, s *tsClient.Service, state serviceResourceModel) serviceResourceModel {
 	model := serviceResourceModel{
 		ID:                      types.StringValue(s.ID),
+		ProjectID:               types.StringValue(s.ProjectID),
 		Password:                state.Password,
 		Name:                    types.StringValue(s.Name),
-		MilliCPU:                types.Int64Value(s.Resources[0].Spec.MilliCPU),
-		MemoryGB:                types.Int64Value(s.Resources[0].Spec.MemoryGB),
-		Hostname:                types.StringValue(s.ServiceSpec.Hostname),
-		Username:                types.StringValue(s.ServiceSpec.Username),
-		Port:                    types.Int64Value(s.ServiceSpec.Port),
+		Hostname:                types.StringValue(s.Spec.Hostname),
+		Username:                types.StringValue(s.Spec.Username),
+		Port:                    types.Int64Value(s.Spec.Port),
 		RegionCode:              types.StringValue(s.RegionCode),
 		Timeouts:                state.Timeouts,
 		EnableHAReplica:         types.BoolValue(s.ReplicaStatus != ""),
-		ReadReplicaSource:       state.ReadReplicaSource,
-		ConnectionPoolerEnabled: types.BoolValue(s.ServiceSpec.Pooler),
-		PoolerHostname:          types.StringValue(s.ServiceSpec.PoolerHostname),
-		PoolerPort:              types.Int64Value(s.ServiceSpec.PoolerPort),
-	}
-	if !s.ServiceSpec.Pooler {
+		ReadReplicaSource:       types.StringNull(),
+		ConnectionPoolerEnabled: types.BoolValue(s.Spec.ConnectionPoolerEnabled),
+		PoolerHostname:          types.StringValue(s.Spec.PoolerHostName),
+		PoolerPort:              types.Int64Value(s.Spec.PoolerPort),
+		VpcID:                   types.Int64Null(),
+		ForkFrom:                state.ForkFrom,
+		ForkedFrom:              forkedFromValue(diags, s),
+	}
+	// The password of an imported service is unrecoverable, and stays null
+	// instead of unknown after it is first updated.
+	if state.Password.IsUnknown() {
+		model.Password = types.StringNull()
+	}
+	if len(s.Resources) > 0 {
+		model.MilliCPU = types.Int64Value(s.Resources[0].Spec.MilliCPU)
+		model.MemoryGB = types.Int64Value(s.Resources[0].Spec.MemoryGB)
+	} else {
+		model.MilliCPU = state.MilliCPU
+		model.MemoryGB = state.MemoryGB
+	}
+	if s.ForkedFromID != nil && s.ForkedFromID.IsStandby {
+		model.ReadReplicaSource = types.StringValue(s.ForkedFromID.ServiceID)
+	}
+	if !s.Spec.ConnectionPoolerEnabled {
 		model.PoolerHostname = types.StringNull()
 		model.PoolerPort = types.Int64Null()
 	}
+	vpcID := s.VPCID
 	if s.VPCEndpoint != nil {
-		if vpcID, err := strconv.ParseInt(s.VPCEndpoint.VPCId, 10, 64); err != nil {
-			diag.AddError("Parse Error", "could not parse vpcID")
-		} else {
-			model.VpcID = types.Int64Value(vpcID)
-		}
+		vpcID = s.VPCEndpoint.VPCID
 		model.Hostname = types.StringValue(s.VPCEndpoint.Host)
 		model.Port = types.Int64Value(s.VPCEndpoint.Port)
 	}
+	// The VPC of a service is known before its endpoint is.
+	if vpcID != "" {
+		if id, err := strconv.ParseInt(vpcID, 10, 64); err != nil {
+			diags.AddError("Parse Error", "could not parse vpcID")
+		} else {
+			model.VpcID = types.Int64Value(id)
+		}
+	}
 
 	return model
 }
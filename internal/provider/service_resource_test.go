@@ -1,14 +1,34 @@
 package provider
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http/httptest"
 	"regexp"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/require"
+
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
+	"github.com/timescale/terraform-provider-timescale/client/fake"
+	"github.com/timescale/terraform-provider-timescale/internal/testserver"
 )
 
 func TestServiceResource_Default_Success(t *testing.T) {
@@ -17,7 +37,7 @@ func TestServiceResource_Default_Success(t *testing.T) {
 		ResourceName: "resource",
 	}
 	resource.ParallelTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: cassetteProviderFactories(t),
 		PreCheck:                 func() { testAccPreCheck(t) },
 		Steps: []resource.TestStep{
 			// Create default and Read testing
@@ -99,7 +119,7 @@ func TestServiceResource_Read_Replica(t *testing.T) {
 	)
 	// Test creating a service with a read replica
 	resource.ParallelTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: cassetteProviderFactories(t),
 		PreCheck:                 func() { testAccPreCheck(t) },
 		Steps: []resource.TestStep{
 			{
@@ -195,7 +215,7 @@ func TestServiceResource_Read_Replica(t *testing.T) {
 
 func TestServiceResource_Timeout(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: cassetteProviderFactories(t),
 		PreCheck:                 func() { testAccPreCheck(t) },
 		Steps: []resource.TestStep{
 			{
@@ -214,7 +234,7 @@ func TestServiceResource_Timeout(t *testing.T) {
 func TestServiceResource_CustomConf(t *testing.T) {
 	// Test resource creation succeeds and update is not allowed
 	resource.ParallelTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: cassetteProviderFactories(t),
 		PreCheck:                 func() { testAccPreCheck(t) },
 		Steps: []resource.TestStep{
 			// Invalid conf millicpu & memory invalid ratio
@@ -263,7 +283,7 @@ func TestServiceResource_CustomConf(t *testing.T) {
 func TestServiceResource_Import(t *testing.T) {
 	config := newServiceConfig(ServiceConfig{Name: "import test"})
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: cassetteProviderFactories(t),
 		PreCheck:                 func() { testAccPreCheck(t) },
 		Steps: []resource.TestStep{
 			// Create the service to import
@@ -327,3 +347,774 @@ func newServiceCustomConfig(resourceName string, config ServiceConfig) string {
 			enable_ha_replica = %t
 		}`, resourceName, config.Name, config.Timeouts.Create, config.MilliCPU, config.MemoryGB, config.RegionCode, config.EnableHAReplica)
 }
+
+func newFakeServiceResource(t *testing.T, client tsClient.API) (*ServiceResource, resourceschema.Schema) {
+	t.Helper()
+	r := &ServiceResource{client: client}
+	var resp fwresource.SchemaResponse
+	r.Schema(context.Background(), fwresource.SchemaRequest{}, &resp)
+	requireNoErrors(t, resp.Diagnostics)
+	return r, resp.Schema
+}
+
+// newServicePlan returns the plan terraform computes for a service with the
+// given name and default settings.
+func newServicePlan(name string) serviceResourceModel {
+	plan := serviceResourceModel{
+		ID:                      types.StringUnknown(),
+		ProjectID:               types.StringUnknown(),
+		Name:                    types.StringUnknown(),
+		Timeouts:                timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{"create": types.StringType})},
+		MilliCPU:                types.Int64Value(DefaultMilliCPU),
+		StorageGB:               types.Int64Null(),
+		MemoryGB:                types.Int64Value(DefaultMemoryGB),
+		Password:                types.StringUnknown(),
+		Hostname:                types.StringUnknown(),
+		Port:                    types.Int64Unknown(),
+		PoolerHostname:          types.StringUnknown(),
+		PoolerPort:              types.Int64Unknown(),
+		Username:                types.StringUnknown(),
+		RegionCode:              types.StringUnknown(),
+		EnableHAReplica:         types.BoolValue(false),
+		ReadReplicaSource:       types.StringNull(),
+		ForkedFrom:              types.ObjectUnknown(forkedFromAttrTypes),
+		VpcID:                   types.Int64Null(),
+		ConnectionPoolerEnabled: types.BoolValue(false),
+	}
+	if name != "" {
+		plan.Name = types.StringValue(name)
+	}
+	return plan
+}
+
+// planFromState returns the plan terraform computes when the configuration
+// of an existing service is left unchanged.
+func planFromState(state serviceResourceModel) serviceResourceModel {
+	plan := state
+	plan.Hostname = types.StringUnknown()
+	plan.Port = types.Int64Unknown()
+	plan.PoolerHostname = types.StringUnknown()
+	plan.PoolerPort = types.Int64Unknown()
+	return plan
+}
+
+func createService(t *testing.T, r *ServiceResource, s resourceschema.Schema, plan serviceResourceModel) (serviceResourceModel, diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+	req := fwresource.CreateRequest{Plan: tfsdk.Plan{Schema: s, Raw: tfValue(t, s, plan)}}
+	resp := fwresource.CreateResponse{State: emptyState(t, s)}
+	r.Create(ctx, req, &resp)
+	var state serviceResourceModel
+	if !resp.Diagnostics.HasError() {
+		requireNoErrors(t, resp.State.Get(ctx, &state))
+	}
+	return state, resp.Diagnostics
+}
+
+func readService(t *testing.T, r *ServiceResource, s resourceschema.Schema, prior serviceResourceModel) (*serviceResourceModel, diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+	state := tfsdk.State{Schema: s, Raw: tfValue(t, s, prior)}
+	resp := fwresource.ReadResponse{State: state}
+	r.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() || resp.State.Raw.IsNull() {
+		return nil, resp.Diagnostics
+	}
+	var model serviceResourceModel
+	requireNoErrors(t, resp.State.Get(ctx, &model))
+	return &model, resp.Diagnostics
+}
+
+func updateService(t *testing.T, r *ServiceResource, s resourceschema.Schema, plan, prior serviceResourceModel) (serviceResourceModel, diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+	state := tfsdk.State{Schema: s, Raw: tfValue(t, s, prior)}
+	req := fwresource.UpdateRequest{Plan: tfsdk.Plan{Schema: s, Raw: tfValue(t, s, plan)}, State: state}
+	resp := fwresource.UpdateResponse{State: state}
+	r.Update(ctx, req, &resp)
+	var model serviceResourceModel
+	if !resp.Diagnostics.HasError() {
+		requireNoErrors(t, resp.State.Get(ctx, &model))
+	}
+	return model, resp.Diagnostics
+}
+
+func TestServiceResource_Fake_Lifecycle(t *testing.T) {
+	client := newFakeClient(t)
+	r, s := newFakeServiceResource(t, client)
+
+	state, diags := createService(t, r, s, newServicePlan("lifecycle"))
+	requireNoErrors(t, diags)
+	require.Equal(t, "lifecycle", state.Name.ValueString())
+	require.NotEmpty(t, state.ID.ValueString())
+	require.NotEmpty(t, state.Password.ValueString())
+	require.NotEmpty(t, state.Hostname.ValueString())
+	require.Equal(t, fake.DefaultRegionCode, state.RegionCode.ValueString())
+	require.False(t, state.EnableHAReplica.ValueBool())
+	require.True(t, state.VpcID.IsNull())
+
+	service, ok := client.Service(state.ID.ValueString())
+	require.True(t, ok)
+	require.Equal(t, "READY", service.Status)
+
+	refreshed, diags := readService(t, r, s, state)
+	requireNoErrors(t, diags)
+	require.Equal(t, state, *refreshed)
+
+	vpc, err := client.CreateVPC(context.Background(), "vpc", "10.0.0.0/21", fake.DefaultRegionCode)
+	require.NoError(t, err)
+	vpcID, err := strconv.ParseInt(vpc.ID, 10, 64)
+	require.NoError(t, err)
+
+	plan := planFromState(state)
+	plan.Name = types.StringValue("renamed")
+	plan.MilliCPU = types.Int64Value(1000)
+	plan.MemoryGB = types.Int64Value(4)
+	plan.EnableHAReplica = types.BoolValue(true)
+	plan.ConnectionPoolerEnabled = types.BoolValue(true)
+	plan.VpcID = types.Int64Value(vpcID)
+	state, diags = updateService(t, r, s, plan, state)
+	requireNoErrors(t, diags)
+	require.Equal(t, "renamed", state.Name.ValueString())
+	require.EqualValues(t, 1000, state.MilliCPU.ValueInt64())
+	require.EqualValues(t, 4, state.MemoryGB.ValueInt64())
+	require.True(t, state.EnableHAReplica.ValueBool())
+	require.True(t, state.ConnectionPoolerEnabled.ValueBool())
+	require.NotEmpty(t, state.PoolerHostname.ValueString())
+	require.Equal(t, vpcID, state.VpcID.ValueInt64())
+
+	plan = planFromState(state)
+	plan.VpcID = types.Int64Null()
+	plan.EnableHAReplica = types.BoolValue(false)
+	state, diags = updateService(t, r, s, plan, state)
+	requireNoErrors(t, diags)
+	require.True(t, state.VpcID.IsNull())
+	require.False(t, state.EnableHAReplica.ValueBool())
+
+	resp := fwresource.DeleteResponse{}
+	r.Delete(context.Background(), fwresource.DeleteRequest{State: tfsdk.State{Schema: s, Raw: tfValue(t, s, state)}}, &resp)
+	requireNoErrors(t, resp.Diagnostics)
+	_, ok = client.Service(state.ID.ValueString())
+	require.False(t, ok)
+
+	// Deleting a service that is already gone succeeds.
+	r.Delete(context.Background(), fwresource.DeleteRequest{State: tfsdk.State{Schema: s, Raw: tfValue(t, s, state)}}, &resp)
+	requireNoErrors(t, resp.Diagnostics)
+}
+
+func TestServiceResource_Fake_ReadRemovesVanishedService(t *testing.T) {
+	client := newFakeClient(t)
+	r, s := newFakeServiceResource(t, client)
+
+	state, diags := createService(t, r, s, newServicePlan("vanished"))
+	requireNoErrors(t, diags)
+	client.RemoveService(state.ID.ValueString())
+
+	refreshed, diags := readService(t, r, s, state)
+	require.Nil(t, refreshed)
+	require.False(t, diags.HasError())
+	require.Len(t, diags.Warnings(), 1)
+	require.Contains(t, diags.Warnings()[0].Detail(), state.ID.ValueString())
+}
+
+func TestServiceResource_ReadRemovesDeletedService(t *testing.T) {
+	// Reads go through the client, to which the test server reports deleted
+	// services with a NOT_FOUND error.
+	server := httptest.NewServer(testserver.New(testserver.Options{}))
+	t.Cleanup(server.Close)
+	client := tsClient.New("project", tsClient.WithToken("token"), tsClient.WithURL(server.URL))
+	r, s := newFakeServiceResource(t, client)
+
+	state, diags := createService(t, r, s, newServicePlan("deleted"))
+	requireNoErrors(t, diags)
+	_, err := client.DeleteService(context.Background(), state.ID.ValueString())
+	require.NoError(t, err)
+
+	refreshed, diags := readService(t, r, s, state)
+	require.Nil(t, refreshed)
+	require.False(t, diags.HasError())
+	require.Len(t, diags.Warnings(), 1)
+	require.Equal(t, "Service Not Found", diags.Warnings()[0].Summary())
+	require.Contains(t, diags.Warnings()[0].Detail(), fmt.Sprintf("Service %s (deleted) no longer exists and has been removed from the state", state.ID.ValueString()))
+}
+
+func TestServiceResource_Fake_ReadFailure(t *testing.T) {
+	client := newFakeClient(t)
+	r, s := newFakeServiceResource(t, client)
+
+	state, diags := createService(t, r, s, newServicePlan("failure"))
+	requireNoErrors(t, diags)
+	client.FailNext("GetService", &tsClient.Error{Message: "expired", Extensions: map[string]any{"code": tsClient.CodeUnauthenticated}})
+
+	_, diags = readService(t, r, s, state)
+	require.True(t, diags.HasError())
+	require.Equal(t, "Authentication Error", diags.Errors()[0].Summary())
+}
+
+func TestServiceResource_Fake_ReadKeepsServiceWithNestedNotFound(t *testing.T) {
+	client := newFakeClient(t)
+	r, s := newFakeServiceResource(t, client)
+
+	state, diags := createService(t, r, s, newServicePlan("partial"))
+	requireNoErrors(t, diags)
+	client.FailNext("GetService", tsClient.Errors{{
+		Message:    "resource not found",
+		Path:       []any{"getService", "resources", 0},
+		Extensions: map[string]any{"code": tsClient.CodeNotFound},
+	}})
+
+	// The service exists, so it must not be removed from the state.
+	_, diags = readService(t, r, s, state)
+	require.True(t, diags.HasError())
+	require.Empty(t, diags.Warnings())
+}
+
+func TestServiceResource_Fake_ReadReplicas(t *testing.T) {
+	client := newFakeClient(t)
+	r, s := newFakeServiceResource(t, client)
+
+	primary, diags := createService(t, r, s, newServicePlan("primary"))
+	requireNoErrors(t, diags)
+
+	replicaPlan := newServicePlan("")
+	replicaPlan.ReadReplicaSource = primary.ID
+	replica, diags := createService(t, r, s, replicaPlan)
+	requireNoErrors(t, diags)
+	require.Equal(t, "replica-primary", replica.Name.ValueString())
+	require.Equal(t, primary.ID, replica.ReadReplicaSource)
+	service, _ := client.Service(replica.ID.ValueString())
+	require.Equal(t, primary.ID.ValueString(), service.ForkedFromID.ServiceID)
+	require.True(t, service.ForkedFromID.IsStandby)
+
+	_, diags = createService(t, r, s, replicaPlan)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags.Errors()[0].Detail(), errMultipleReadReplicas)
+
+	replicaOfReplica := newServicePlan("")
+	replicaOfReplica.ReadReplicaSource = replica.ID
+	_, diags = createService(t, r, s, replicaOfReplica)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags.Errors()[0].Detail(), errReplicaFromFork)
+
+	other, diags := createService(t, r, s, newServicePlan("other"))
+	requireNoErrors(t, diags)
+	replicaWithHA := newServicePlan("")
+	replicaWithHA.ReadReplicaSource = other.ID
+	replicaWithHA.EnableHAReplica = types.BoolValue(true)
+	_, diags = createService(t, r, s, replicaWithHA)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags.Errors()[0].Detail(), errReplicaWithHA)
+
+	plan := planFromState(replica)
+	plan.ReadReplicaSource = other.ID
+	_, diags = updateService(t, r, s, plan, replica)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags.Errors()[0].Detail(), errUpdateReplicaSource)
+}
+
+func TestServiceResource_Fake_OtherProject(t *testing.T) {
+	client := newFakeClient(t)
+	r, s := newFakeServiceResource(t, client)
+	other := client.Project("other")
+
+	plan := newServicePlan("elsewhere")
+	plan.ProjectID = types.StringValue("other")
+	state, diags := createService(t, r, s, plan)
+	requireNoErrors(t, diags)
+	require.Equal(t, "other", state.ProjectID.ValueString())
+	_, ok := other.Service(state.ID.ValueString())
+	require.True(t, ok)
+	_, ok = client.Service(state.ID.ValueString())
+	require.False(t, ok)
+
+	refreshed, diags := readService(t, r, s, state)
+	requireNoErrors(t, diags)
+	require.Equal(t, state, *refreshed)
+
+	plan = planFromState(state)
+	plan.Name = types.StringValue("renamed")
+	state, diags = updateService(t, r, s, plan, state)
+	requireNoErrors(t, diags)
+	service, _ := other.Service(state.ID.ValueString())
+	require.Equal(t, "renamed", service.Name)
+
+	deleteResp := fwresource.DeleteResponse{}
+	r.Delete(context.Background(), fwresource.DeleteRequest{State: tfsdk.State{Schema: s, Raw: tfValue(t, s, state)}}, &deleteResp)
+	requireNoErrors(t, deleteResp.Diagnostics)
+	_, ok = other.Service(state.ID.ValueString())
+	require.False(t, ok)
+
+	// Services of the provider's project record it too.
+	state, diags = createService(t, r, s, newServicePlan("default"))
+	requireNoErrors(t, diags)
+	require.Equal(t, fake.DefaultProjectID, state.ProjectID.ValueString())
+}
+
+func TestServiceResource_ImportState(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+	r, s := newFakeServiceResource(t, client)
+	ingest, diags := createService(t, r, s, newServicePlan("ingest-prod"))
+	requireNoErrors(t, diags)
+	plan := newServicePlan("ingest-prod")
+	plan.ProjectID = types.StringValue("other")
+	otherIngest, diags := createService(t, r, s, plan)
+	requireNoErrors(t, diags)
+
+	importState := func(id string) (types.String, types.String, diag.Diagnostics) {
+		resp := fwresource.ImportStateResponse{State: emptyState(t, s)}
+		r.ImportState(ctx, fwresource.ImportStateRequest{ID: id}, &resp)
+		var projectID, serviceID types.String
+		resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("project_id"), &projectID)...)
+		resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("id"), &serviceID)...)
+		return projectID, serviceID, resp.Diagnostics
+	}
+	for id, want := range map[string][2]string{
+		"svc0000001":                     {"", "svc0000001"},
+		"other/svc0000001":               {"other", "svc0000001"},
+		"id:svc0000001":                  {"", "svc0000001"},
+		"project:other/id:svc0000001":    {"other", "svc0000001"},
+		"name:ingest-prod":               {"", ingest.ID.ValueString()},
+		"project:other/name:ingest-prod": {"other", otherIngest.ID.ValueString()},
+	} {
+		projectID, serviceID, diags := importState(id)
+		requireNoErrors(t, diags)
+		require.Equal(t, want[0], projectID.ValueString(), id)
+		require.Equal(t, want[1], serviceID.ValueString(), id)
+	}
+
+	// Imported by name, a service is read like any other.
+	importResp := fwresource.ImportStateResponse{State: emptyState(t, s)}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: "project:other/name:ingest-prod"}, &importResp)
+	requireNoErrors(t, importResp.Diagnostics)
+	readResp := fwresource.ReadResponse{State: importResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: importResp.State}, &readResp)
+	requireNoErrors(t, readResp.Diagnostics)
+	var imported serviceResourceModel
+	requireNoErrors(t, readResp.State.Get(ctx, &imported))
+	require.Equal(t, otherIngest.ID, imported.ID)
+	require.Equal(t, "other", imported.ProjectID.ValueString())
+	require.Equal(t, "ingest-prod", imported.Name.ValueString())
+
+	_, _, diags = importState("name:missing")
+	require.True(t, diags.HasError())
+	require.Contains(t, diags.Errors()[0].Detail(), `no service is named "missing"`)
+
+	duplicate, diags := createService(t, r, s, newServicePlan("ingest-prod"))
+	requireNoErrors(t, diags)
+	_, _, diags = importState("name:ingest-prod")
+	require.True(t, diags.HasError())
+	require.Contains(t, diags.Errors()[0].Detail(), `2 services are named "ingest-prod"`)
+	require.Contains(t, diags.Errors()[0].Detail(), duplicate.ID.ValueString())
+	require.Contains(t, diags.Errors()[0].Detail(), "id:<service_id>")
+
+	for _, id := range []string{"other/", "name:", "project:other", "project:other/svc0000001"} {
+		_, _, diags = importState(id)
+		require.True(t, diags.HasError(), id)
+		require.Equal(t, "Invalid Import ID", diags.Errors()[0].Summary(), id)
+	}
+}
+
+func TestServiceResource_Fake_Fork(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+	r, s := newFakeServiceResource(t, client)
+	prod, diags := createService(t, r, s, newServicePlan("prod"))
+	requireNoErrors(t, diags)
+
+	plan := newServicePlan("")
+	plan.ForkFrom = &serviceForkFromModel{
+		ServiceID:  prod.ID,
+		ProjectID:  types.StringNull(),
+		Strategy:   types.StringValue(tsClient.ForkStrategyNow),
+		TargetTime: types.StringNull(),
+	}
+	staging, diags := createService(t, r, s, plan)
+	requireNoErrors(t, diags)
+	require.Equal(t, "fork-prod", staging.Name.ValueString())
+	require.Equal(t, plan.ForkFrom, staging.ForkFrom)
+	var lineage forkedFromModel
+	requireNoErrors(t, staging.ForkedFrom.As(ctx, &lineage, basetypes.ObjectAsOptions{}))
+	require.Equal(t, prod.ID, lineage.ServiceID)
+	require.Equal(t, fake.DefaultProjectID, lineage.ProjectID.ValueString())
+	require.False(t, lineage.IsStandby.ValueBool())
+	service, _ := client.Service(staging.ID.ValueString())
+	require.Equal(t, prod.ID.ValueString(), service.ForkedFromID.ServiceID)
+
+	// fork_from is kept, and the lineage read, on refresh.
+	refreshed, diags := readService(t, r, s, staging)
+	requireNoErrors(t, diags)
+	require.Equal(t, staging, *refreshed)
+
+	// Services are forked from other projects.
+	other, err := client.Project("other").CreateService(ctx, tsClient.CreateServiceRequest{Name: "elsewhere", MilliCPU: "500", MemoryGB: "2"})
+	require.NoError(t, err)
+	plan = newServicePlan("from-other")
+	plan.ForkFrom = &serviceForkFromModel{
+		ServiceID:  types.StringValue(other.Service.ID),
+		ProjectID:  types.StringValue("other"),
+		Strategy:   types.StringNull(),
+		TargetTime: types.StringNull(),
+	}
+	fork, diags := createService(t, r, s, plan)
+	requireNoErrors(t, diags)
+	service, _ = client.Service(fork.ID.ValueString())
+	require.Equal(t, "other", service.ForkedFromID.ProjectID)
+	require.Equal(t, other.Service.ID, service.ForkedFromID.ServiceID)
+
+	plan.ForkFrom.ProjectID = types.StringNull()
+	_, diags = createService(t, r, s, plan)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags.Errors()[0].Detail(), "unable to get service "+other.Service.ID+" to fork")
+
+	// Sources default to the project of the fork, not to the provider's.
+	plan.ProjectID = types.StringValue("other")
+	fork, diags = createService(t, r, s, plan)
+	requireNoErrors(t, diags)
+	service, ok := client.Project("other").Service(fork.ID.ValueString())
+	require.True(t, ok)
+	require.Equal(t, "other", service.ForkedFromID.ProjectID)
+	require.Equal(t, other.Service.ID, service.ForkedFromID.ServiceID)
+}
+
+func TestServiceResource_Fake_PointInTimeRestore(t *testing.T) {
+	client := newFakeClient(t)
+	r, s := newFakeServiceResource(t, client)
+	prod, diags := createService(t, r, s, newServicePlan("prod"))
+	requireNoErrors(t, diags)
+	source, _ := client.Service(prod.ID.ValueString())
+	created, err := time.Parse(time.RFC3339, source.Created)
+	require.NoError(t, err)
+
+	restore := func(targetTime time.Time) (serviceResourceModel, diag.Diagnostics) {
+		plan := newServicePlan("before-migration")
+		plan.ForkFrom = &serviceForkFromModel{
+			ServiceID:  prod.ID,
+			ProjectID:  types.StringNull(),
+			Strategy:   types.StringValue(tsClient.ForkStrategyPITR),
+			TargetTime: types.StringValue(targetTime.Format(time.RFC3339)),
+		}
+		return createService(t, r, s, plan)
+	}
+
+	restored, diags := restore(created)
+	requireNoErrors(t, diags)
+	require.Equal(t, tsClient.ForkStrategyPITR, restored.ForkFrom.Strategy.ValueString())
+	service, _ := client.Service(restored.ID.ValueString())
+	require.Equal(t, prod.ID.ValueString(), service.ForkedFromID.ServiceID)
+	require.Equal(t, "READY", service.Status)
+
+	// Times outside of the recovery window are rejected before creating
+	// anything.
+	for _, target := range []time.Time{created.Add(-time.Hour), time.Now().Add(time.Hour)} {
+		_, diags = restore(target)
+		require.True(t, diags.HasError())
+		require.Contains(t, diags.Errors()[0].Detail(), "can only be restored to a time between "+source.Created)
+	}
+	client.FailNext("GetRecoveryWindow", errors.New("boom"))
+	_, diags = restore(created)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags.Errors()[0].Detail(), "unable to get the recovery window of service "+prod.ID.ValueString())
+
+	// Without recovery windows, the API alone validates the target time.
+	client.FailNext("GetRecoveryWindow", &tsClient.Error{
+		Message:    `Cannot query field "getServiceRecoveryWindow" on type "Query".`,
+		Extensions: map[string]any{"code": tsClient.CodeValidationFailed},
+	})
+	_, diags = restore(created)
+	requireNoErrors(t, diags)
+	require.Len(t, diags.Warnings(), 1)
+	require.Equal(t, "Recovery Window Unavailable", diags.Warnings()[0].Summary())
+	client.FailNext("GetRecoveryWindow", &tsClient.Error{Extensions: map[string]any{"code": tsClient.CodeValidationFailed}})
+	_, diags = restore(created.Add(-time.Hour))
+	require.True(t, diags.HasError())
+	require.Contains(t, diags.Errors()[0].Detail(), "outside the recovery window")
+
+	services, err := client.GetAllServices(context.Background())
+	require.NoError(t, err)
+	require.Len(t, services, 3)
+}
+
+func TestServiceToResource_ReportsErrors(t *testing.T) {
+	service := &tsClient.Service{ID: "svc0000001", VPCEndpoint: &tsClient.VPCEndpoint{VPCID: "not-a-number"}}
+	var diags diag.Diagnostics
+	serviceToResource(&diags, service, newServicePlan("broken"))
+	require.True(t, diags.HasError())
+	require.Equal(t, "could not parse vpcID", diags.Errors()[0].Detail())
+
+	diags = nil
+	serviceToDataModel(&diags, service)
+	require.True(t, diags.HasError())
+}
+
+func TestServiceResource_ForkFromRequiresReplace(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+	r, s := newFakeServiceResource(t, client)
+	prod, diags := createService(t, r, s, newServicePlan("prod"))
+	requireNoErrors(t, diags)
+	created, err := client.CreateService(ctx, tsClient.CreateServiceRequest{
+		Name: "staging", MilliCPU: "500", MemoryGB: "2",
+		ForkConfig: &tsClient.ForkConfig{ProjectID: fake.DefaultProjectID, ServiceID: prod.ID.ValueString()},
+	})
+	require.NoError(t, err)
+	imported, diags := importService(t, r, s, created.Service.ID)
+	requireNoErrors(t, diags)
+	require.Nil(t, imported.ForkFrom)
+
+	forkFrom := func(serviceID string) types.Object {
+		value, diags := types.ObjectValueFrom(ctx, s.Blocks["fork_from"].Type().(types.ObjectType).AttrTypes, serviceForkFromModel{
+			ServiceID:  types.StringValue(serviceID),
+			ProjectID:  types.StringNull(),
+			Strategy:   types.StringNull(),
+			TargetTime: types.StringNull(),
+		})
+		requireNoErrors(t, diags)
+		return value
+	}
+	requiresReplace := func(stateValue, planValue types.Object) bool {
+		state := tfsdk.State{Schema: s, Raw: tfValue(t, s, imported)}
+		req := planmodifier.ObjectRequest{Path: path.Root("fork_from"), State: state, StateValue: stateValue, PlanValue: planValue}
+		resp := objectplanmodifier.RequiresReplaceIfFuncResponse{}
+		requiresForkReplace(ctx, req, &resp)
+		requireNoErrors(t, resp.Diagnostics)
+		return resp.RequiresReplace
+	}
+	null := types.ObjectNull(s.Blocks["fork_from"].Type().(types.ObjectType).AttrTypes)
+	// Adding the block of its source to an imported fork keeps it.
+	require.False(t, requiresReplace(null, forkFrom(prod.ID.ValueString())))
+	require.True(t, requiresReplace(null, forkFrom("svc9999999")))
+	require.True(t, requiresReplace(forkFrom(prod.ID.ValueString()), forkFrom("svc9999999")))
+	// Removing the block keeps the fork.
+	require.False(t, requiresReplace(forkFrom(prod.ID.ValueString()), null))
+}
+
+func TestServiceResource_Fake_RemoveForkFrom(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+	r, s := newFakeServiceResource(t, client)
+	prod, diags := createService(t, r, s, newServicePlan("prod"))
+	requireNoErrors(t, diags)
+	plan := newServicePlan("staging")
+	plan.ForkFrom = &serviceForkFromModel{
+		ServiceID:  prod.ID,
+		ProjectID:  types.StringNull(),
+		Strategy:   types.StringNull(),
+		TargetTime: types.StringNull(),
+	}
+	staging, diags := createService(t, r, s, plan)
+	requireNoErrors(t, diags)
+
+	// Plan the removal of the block with the plan modifiers of fork_from,
+	// as terraform does.
+	plan = planFromState(staging)
+	plan.ForkFrom = nil
+	state := tfsdk.State{Schema: s, Raw: tfValue(t, s, staging)}
+	planned := tfsdk.Plan{Schema: s, Raw: tfValue(t, s, plan)}
+	var stateValue, planValue types.Object
+	requireNoErrors(t, state.GetAttribute(ctx, path.Root("fork_from"), &stateValue))
+	requireNoErrors(t, planned.GetAttribute(ctx, path.Root("fork_from"), &planValue))
+	req := planmodifier.ObjectRequest{
+		Path:       path.Root("fork_from"),
+		Config:     tfsdk.Config{Schema: s, Raw: tfValue(t, s, plan)},
+		State:      state,
+		StateValue: stateValue,
+		Plan:       planned,
+		PlanValue:  planValue,
+	}
+	for _, modifier := range s.Blocks["fork_from"].(resourceschema.SingleNestedBlock).PlanModifiers {
+		resp := planmodifier.ObjectResponse{PlanValue: req.PlanValue}
+		modifier.PlanModifyObject(ctx, req, &resp)
+		requireNoErrors(t, resp.Diagnostics)
+		require.False(t, resp.RequiresReplace)
+	}
+
+	updated, diags := updateService(t, r, s, plan, staging)
+	requireNoErrors(t, diags)
+	require.Equal(t, staging.ID, updated.ID)
+	require.Nil(t, updated.ForkFrom)
+	require.Equal(t, staging.ForkedFrom, updated.ForkedFrom)
+	services, err := client.GetAllServices(ctx)
+	require.NoError(t, err)
+	require.Len(t, services, 2)
+}
+
+func TestServiceResource_ForkFromValidator(t *testing.T) {
+	ctx := context.Background()
+	_, s := newFakeServiceResource(t, newFakeClient(t))
+	attrTypes := s.Blocks["fork_from"].Type().(types.ObjectType).AttrTypes
+	validate := func(strategy, targetTime types.String) diag.Diagnostics {
+		value, diags := types.ObjectValueFrom(ctx, attrTypes, serviceForkFromModel{
+			ServiceID:  types.StringValue("svc0000001"),
+			ProjectID:  types.StringNull(),
+			Strategy:   strategy,
+			TargetTime: targetTime,
+		})
+		requireNoErrors(t, diags)
+		resp := validator.ObjectResponse{}
+		forkFromValidator{}.ValidateObject(ctx, validator.ObjectRequest{Path: path.Root("fork_from"), ConfigValue: value}, &resp)
+		return resp.Diagnostics
+	}
+	pitr := types.StringValue(tsClient.ForkStrategyPITR)
+	requireNoErrors(t, validate(types.StringNull(), types.StringNull()))
+	requireNoErrors(t, validate(types.StringValue(tsClient.ForkStrategyNow), types.StringNull()))
+	requireNoErrors(t, validate(pitr, types.StringValue("2024-05-01T12:00:00Z")))
+	requireNoErrors(t, validate(pitr, types.StringUnknown()))
+	for detail, diags := range map[string]diag.Diagnostics{
+		"target_time is required":       validate(pitr, types.StringNull()),
+		"target_time is only supported": validate(types.StringNull(), types.StringValue("2024-05-01T12:00:00Z")),
+		"is not an RFC 3339 time":       validate(pitr, types.StringValue("yesterday")),
+	} {
+		require.True(t, diags.HasError(), detail)
+		require.Contains(t, diags.Errors()[0].Detail(), detail)
+	}
+}
+
+// importService imports a service and reads it, as terraform import does.
+func importService(t *testing.T, r *ServiceResource, s resourceschema.Schema, id string) (serviceResourceModel, diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+	importResp := fwresource.ImportStateResponse{State: emptyState(t, s)}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: id}, &importResp)
+	if importResp.Diagnostics.HasError() {
+		return serviceResourceModel{}, importResp.Diagnostics
+	}
+	readResp := fwresource.ReadResponse{State: importResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: importResp.State}, &readResp)
+	diags := append(importResp.Diagnostics, readResp.Diagnostics...)
+	var model serviceResourceModel
+	if !diags.HasError() {
+		requireNoErrors(t, readResp.State.Get(ctx, &model))
+	}
+	return model, diags
+}
+
+func TestServiceResource_Fake_ImportHydratesState(t *testing.T) {
+	client := newFakeClient(t)
+	r, s := newFakeServiceResource(t, client)
+	vpc, err := client.CreateVPC(context.Background(), "vpc", "10.0.0.0/21", fake.DefaultRegionCode)
+	require.NoError(t, err)
+	vpcID, err := strconv.ParseInt(vpc.ID, 10, 64)
+	require.NoError(t, err)
+
+	plan := newServicePlan("primary")
+	plan.EnableHAReplica = types.BoolValue(true)
+	plan.ConnectionPoolerEnabled = types.BoolValue(true)
+	plan.VpcID = types.Int64Value(vpcID)
+	primary, diags := createService(t, r, s, plan)
+	requireNoErrors(t, diags)
+	plan = newServicePlan("replica")
+	plan.ReadReplicaSource = primary.ID
+	replica, diags := createService(t, r, s, plan)
+	requireNoErrors(t, diags)
+
+	// Imported services have the state they were created with, but for the
+	// password and the timeouts.
+	for _, created := range []serviceResourceModel{primary, replica} {
+		imported, diags := importService(t, r, s, created.ID.ValueString())
+		requireNoErrors(t, diags)
+		require.Len(t, diags.Warnings(), 1)
+		require.Equal(t, "Password Not Imported", diags.Warnings()[0].Summary())
+		require.True(t, imported.Password.IsNull())
+		want := created
+		want.Password = imported.Password
+		want.Timeouts = imported.Timeouts
+		require.Equal(t, want, imported)
+	}
+
+	// Updates keep the unrecoverable password null rather than unknown.
+	imported, diags := importService(t, r, s, "name:replica")
+	requireNoErrors(t, diags)
+	plan = planFromState(imported)
+	plan.Password = types.StringUnknown()
+	plan.Name = types.StringValue("renamed")
+	updated, diags := updateService(t, r, s, plan, imported)
+	requireNoErrors(t, diags)
+	require.True(t, updated.Password.IsNull())
+	require.Equal(t, primary.ID, updated.ReadReplicaSource)
+}
+
+func TestServiceResource_Fake_UpdateIgnoresStaleReady(t *testing.T) {
+	tests := map[string]struct {
+		transitions []string
+	}{
+		"waits for the pending statuses": {},
+		// The change is applied without any pending status the watcher
+		// sees, so the service is read again after the grace period.
+		"reads the service again after the grace period": {transitions: []string{"READY"}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := newFakeClient(t)
+			client.Transitions = test.transitions
+			client.StaleReadyDelay = 20 * time.Millisecond
+			r, s := newFakeServiceResource(t, client)
+
+			state, diags := createService(t, r, s, newServicePlan("stale"))
+			requireNoErrors(t, diags)
+
+			plan := planFromState(state)
+			plan.MilliCPU = types.Int64Value(1000)
+			plan.MemoryGB = types.Int64Value(4)
+			plan.ConnectionPoolerEnabled = types.BoolValue(true)
+			state, diags = updateService(t, r, s, plan, state)
+			requireNoErrors(t, diags)
+			require.EqualValues(t, 1000, state.MilliCPU.ValueInt64())
+			require.EqualValues(t, 4, state.MemoryGB.ValueInt64())
+			require.True(t, state.ConnectionPoolerEnabled.ValueBool())
+			require.NotEmpty(t, state.PoolerHostname.ValueString())
+		})
+	}
+}
+
+func TestServiceResource_Fake_WaitForServiceReadiness(t *testing.T) {
+	ctx := context.Background()
+	noTimeouts := timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{"create": types.StringType})}
+
+	t.Run("goes through pending states", func(t *testing.T) {
+		client := newFakeClient(t)
+		client.Transitions = []string{"QUEUED", "CONFIGURING", "UNSTABLE", "CONFIGURING", "READY"}
+		r, _ := newFakeServiceResource(t, client)
+		created, err := client.CreateService(ctx, tsClient.CreateServiceRequest{MilliCPU: "500", MemoryGB: "2"})
+		require.NoError(t, err)
+
+		service, err := waitForServiceReadiness(ctx, r.client, created.Service.ID, noTimeouts, 0)
+		require.NoError(t, err)
+		require.Equal(t, "READY", service.Status)
+	})
+
+	t.Run("fails on unexpected state", func(t *testing.T) {
+		client := newFakeClient(t)
+		client.Transitions = []string{"QUEUED", "FAILED"}
+		r, _ := newFakeServiceResource(t, client)
+		created, err := client.CreateService(ctx, tsClient.CreateServiceRequest{MilliCPU: "500", MemoryGB: "2"})
+		require.NoError(t, err)
+
+		_, err = waitForServiceReadiness(ctx, r.client, created.Service.ID, noTimeouts, 0)
+		require.ErrorContains(t, err, "FAILED")
+	})
+
+	t.Run("fails on client error", func(t *testing.T) {
+		client := newFakeClient(t)
+		r, _ := newFakeServiceResource(t, client)
+		created, err := client.CreateService(ctx, tsClient.CreateServiceRequest{MilliCPU: "500", MemoryGB: "2"})
+		require.NoError(t, err)
+		client.FailNext("GetService", errors.New("boom"))
+
+		_, err = waitForServiceReadiness(ctx, r.client, created.Service.ID, noTimeouts, 0)
+		require.ErrorContains(t, err, "boom")
+	})
+
+	t.Run("create deletes services that never become ready", func(t *testing.T) {
+		client := newFakeClient(t)
+		client.Transitions = []string{"QUEUED", "FAILED"}
+		r, s := newFakeServiceResource(t, client)
+
+		_, diags := createService(t, r, s, newServicePlan("never ready"))
+		require.True(t, diags.HasError())
+		require.Equal(t, ErrCreateTimeout, diags.Errors()[0].Summary())
+		services, err := client.GetAllServices(ctx)
+		require.NoError(t, err)
+		require.Empty(t, services)
+	})
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans of the provider.
+const tracerName = "github.com/timescale/terraform-provider-timescale/internal/provider"
+
+// traceParentEnvVar links the spans of the provider to a trace started by
+// the program running Terraform, in the W3C traceparent format.
+const traceParentEnvVar = "TRACEPARENT"
+
+// parentSpan is the span context read from traceParentEnvVar, if any.
+var parentSpan trace.SpanContext
+
+// tracingEnabled tells whether the standard OpenTelemetry environment
+// variables ask for traces to be exported over OTLP.
+func tracingEnabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	switch os.Getenv("OTEL_TRACES_EXPORTER") {
+	case "none":
+		return false
+	case "otlp":
+		return true
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// StartTracing exports the spans of the provider over OTLP/HTTP when the
+// standard OpenTelemetry environment variables enable it, and returns a
+// function flushing the pending spans. Spans are dropped otherwise.
+func StartTracing(ctx context.Context, version string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if !tracingEnabled() {
+		return noop, nil
+	}
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return noop, err
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			attribute.String("service.name", "terraform-provider-timescale"),
+			attribute.String("service.version", version),
+		),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return noop, err
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	propagator := propagation.TraceContext{}
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator)
+
+	carrier := propagation.MapCarrier{"traceparent": os.Getenv(traceParentEnvVar)}
+	parentSpan = trace.SpanContextFromContext(propagator.Extract(ctx, carrier))
+	return tp.Shutdown, nil
+}
+
+// startSpan starts a span for an operation of the provider. Operations
+// called without a trace are linked to the trace of traceParentEnvVar.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() && parentSpan.IsValid() {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, parentSpan)
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends the span of an operation, marking it failed when diags has
+// errors.
+func endSpan(span trace.Span, diags diag.Diagnostics) {
+	if diags.HasError() {
+		errs := diags.Errors()
+		span.SetStatus(codes.Error, errs[0].Summary()+": "+errs[0].Detail())
+	}
+	span.End()
+}
+
+// endSpanWithError ends the span of an operation, marking it failed when
+// err is not nil.
+func endSpanWithError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
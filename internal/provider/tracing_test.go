@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
+	"github.com/timescale/terraform-provider-timescale/internal/testserver"
+)
+
+// collector is a stand-in of an OTLP/HTTP collector recording the spans it
+// receives.
+type collector struct {
+	t     *testing.T
+	mu    sync.Mutex
+	spans []*tracepb.Span
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	require.Equal(c.t, "/v1/traces", r.URL.Path)
+	body, err := io.ReadAll(r.Body)
+	require.NoError(c.t, err)
+	var req coltracepb.ExportTraceServiceRequest
+	require.NoError(c.t, proto.Unmarshal(body, &req))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, resourceSpans := range req.ResourceSpans {
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			c.spans = append(c.spans, scopeSpans.Spans...)
+		}
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	out, _ := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+	_, _ = w.Write(out)
+}
+
+// named returns the spans with the given name.
+func (c *collector) named(name string) []*tracepb.Span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var spans []*tracepb.Span
+	for _, span := range c.spans {
+		if span.Name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func TestStartTracing_Disabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_TRACES_EXPORTER", "")
+	require.False(t, tracingEnabled())
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	require.True(t, tracingEnabled())
+	t.Setenv("OTEL_TRACES_EXPORTER", "none")
+	require.False(t, tracingEnabled())
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp")
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	require.False(t, tracingEnabled())
+}
+
+func TestStartTracing_ExportsSpans(t *testing.T) {
+	c := &collector{t: t}
+	collectorServer := httptest.NewServer(c)
+	t.Cleanup(collectorServer.Close)
+	const traceID, parentID = "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", collectorServer.URL)
+	t.Setenv("OTEL_TRACES_EXPORTER", "")
+	t.Setenv("OTEL_SDK_DISABLED", "")
+	t.Setenv(traceParentEnvVar, "00-"+traceID+"-"+parentID+"-01")
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+		parentSpan = trace.SpanContext{}
+	})
+	polling := serviceReadinessPolling
+	serviceReadinessPolling = tsClient.PollPolicy{MinInterval: 10 * time.Millisecond, MaxInterval: 10 * time.Millisecond}
+	t.Cleanup(func() { serviceReadinessPolling = polling })
+
+	ctx := context.Background()
+	shutdown, err := StartTracing(ctx, "test")
+	require.NoError(t, err)
+
+	var traceParents []string
+	api := testserver.New(testserver.Options{})
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParents = append(traceParents, r.Header.Get("traceparent"))
+		api.ServeHTTP(w, r)
+	}))
+	t.Cleanup(apiServer.Close)
+	client := tsClient.New("project", tsClient.WithToken("token"), tsClient.WithURL(apiServer.URL))
+
+	// A data source read.
+	d := &vpcsDataSource{client: client}
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	readResp := &datasource.ReadResponse{State: tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}}
+	d.Read(ctx, datasource.ReadRequest{Config: tfsdk.Config{Schema: readResp.State.Schema, Raw: readResp.State.Raw}}, readResp)
+	require.False(t, readResp.Diagnostics.HasError(), readResp.Diagnostics)
+
+	// Polls of a service until it is ready.
+	created, err := client.CreateService(ctx, tsClient.CreateServiceRequest{Name: "svc", MilliCPU: "500", MemoryGB: "2", RegionCode: "us-east-1"})
+	require.NoError(t, err)
+	r := &ServiceResource{client: client}
+	noTimeouts := timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{"create": types.StringType})}
+	_, err = waitForServiceReadiness(ctx, r.client, created.Service.ID, noTimeouts, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, shutdown(ctx))
+
+	reads := c.named("vpcsDataSource.Read")
+	require.Len(t, reads, 1)
+	require.Equal(t, traceID, hex.EncodeToString(reads[0].TraceId))
+	require.Equal(t, parentID, hex.EncodeToString(reads[0].ParentSpanId))
+	calls := c.named("graphql GetAllVPCs")
+	require.Len(t, calls, 1)
+	require.Equal(t, reads[0].SpanId, calls[0].ParentSpanId)
+	require.Equal(t, tracepb.Span_SPAN_KIND_CLIENT, calls[0].Kind)
+	require.Contains(t, traceParents[0], hex.EncodeToString(calls[0].SpanId))
+
+	waits := c.named("ServiceResource.waitForServiceReadiness")
+	require.Len(t, waits, 1)
+	polls := c.named("StatusWatcher.poll")
+	require.NotEmpty(t, polls)
+	gets := c.named("graphql GetService")
+	require.Len(t, gets, len(polls))
+	for i, poll := range polls {
+		require.Equal(t, waits[0].SpanId, poll.ParentSpanId)
+		require.Equal(t, poll.SpanId, gets[i].ParentSpanId)
+	}
+}
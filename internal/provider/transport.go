@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
+)
+
+// newTransport returns the transport set up by the connection attributes of
+// the provider block.
+func newTransport(data TimescaleProviderModel, diags *diag.Diagnostics) http.RoundTripper {
+	config := tsClient.TransportConfig{
+		ProxyURL:          data.ProxyURL.ValueString(),
+		CABundle:          readPEM(data.CABundle, "ca_bundle", diags),
+		ClientCertificate: readPEM(data.ClientCertificate, "client_certificate", diags),
+		ClientKey:         readPEM(data.ClientKey, "client_key", diags),
+		KeepAlive:         parseDuration(data.KeepAlive, "keep_alive", tsClient.DefaultKeepAlive, diags),
+	}
+	if diags.HasError() {
+		return nil
+	}
+	transport, err := tsClient.NewTransport(config)
+	if err != nil {
+		diags.AddError("Invalid Transport Settings", fmt.Sprintf("Unable to set up the connection to the Timescale API: %s.", err))
+		return nil
+	}
+	return transport
+}
+
+// readPEM returns the PEM data set by an attribute, either inline or as the
+// path of a file.
+func readPEM(value types.String, attribute string, diags *diag.Diagnostics) []byte {
+	if value.IsNull() || value.ValueString() == "" {
+		return nil
+	}
+	if strings.Contains(value.ValueString(), "-----BEGIN ") {
+		return []byte(value.ValueString())
+	}
+	data, err := os.ReadFile(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root(attribute), "Invalid Attribute Value",
+			fmt.Sprintf("%s must hold PEM data or the path of a file holding it: %s", attribute, err))
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = urlValidator{}
+
+// urlValidator validates that a string attribute is an absolute http or
+// https URL, or a proxy URL.
+type urlValidator struct {
+	// proxy also accepts socks5 URLs.
+	proxy bool
+}
+
+func (v urlValidator) Description(_ context.Context) string {
+	if v.proxy {
+		return "value must be an absolute http, https or socks5 URL"
+	}
+	return "value must be an absolute http or https URL"
+}
+
+func (v urlValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v urlValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	validate := validateURL
+	if v.proxy {
+		validate = validateProxyURL
+	}
+	if err := validate(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Attribute Value", err.Error())
+	}
+}
+
+// validateURL returns an error if value is not an absolute http or https URL.
+func validateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid URL: %w", value, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q must be an absolute http or https URL, such as %q", value, "https://console.cloud.timescale.com/api/query")
+	}
+	return nil
+}
+
+// validateProxyURL returns an error if value is not an absolute http, https
+// or socks5 URL.
+func validateProxyURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid URL: %w", value, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") || u.Host == "" {
+		return fmt.Errorf("%q must be an absolute http, https or socks5 URL, such as %q", value, "http://proxy.example.com:3128")
+	}
+	return nil
+}
@@ -18,13 +18,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
-	tsClient "github.com/timescale/terraform-provider-timescale/internal/client"
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &vpcResource{}
-	_ resource.ResourceWithConfigure = &vpcResource{}
+	_ resource.Resource                = &vpcResource{}
+	_ resource.ResourceWithConfigure   = &vpcResource{}
+	_ resource.ResourceWithImportState = &vpcResource{}
 
 	ErrVPCRead   = "Error reading VPC"
 	ErrVPCCreate = "Error creating VPC"
@@ -40,7 +41,7 @@ func NewVpcsResource() resource.Resource {
 
 // vpcResource is the data source implementation.
 type vpcResource struct {
-	client *tsClient.Client
+	client tsClient.API
 }
 
 type vpcResourceModel struct {
@@ -107,6 +108,8 @@ func (r *vpcResource) Metadata(_ context.Context, req resource.MetadataRequest,
 
 // Read refreshes the Terraform state with the latest data.
 func (r *vpcResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startSpan(ctx, "vpcResource.Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
 	var state vpcResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
@@ -118,10 +121,24 @@ func (r *vpcResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	var err error
 
 	if !state.Name.IsNull() {
+		client := projectClient(r.client, state.ProjectID)
 		tflog.Info(ctx, "Getting VPC by name: "+state.Name.ValueString())
-		vpc, err = r.client.GetVPCByName(ctx, state.Name.ValueString())
+		vpc, err = client.GetVPCByName(ctx, state.Name.ValueString())
+		if tsClient.IsNotFound(err) && !state.ID.IsNull() && !state.ID.IsUnknown() {
+			// The VPC may have been renamed outside of Terraform, e.g. in
+			// the console.
+			tflog.Info(ctx, fmt.Sprintf("Getting VPC by ID: %d", state.ID.ValueInt64()))
+			vpc, err = client.GetVPCByID(ctx, state.ID.ValueInt64())
+		}
+		if tsClient.IsNotFound(err) {
+			resp.Diagnostics.AddWarning("VPC Not Found",
+				fmt.Sprintf("VPC %s (%d) no longer exists and has been removed from the state, it will be recreated on the next apply.",
+					state.Name.ValueString(), state.ID.ValueInt64()))
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to Read vpc, got error: %s, %s", state.Name.ValueString(), err))
+			resp.Diagnostics.AddError(clientErrorSummary(err), fmt.Sprintf("Unable to Read vpc, got error: %s, %s", state.Name.ValueString(), err))
 			return
 		}
 	} else {
@@ -200,6 +217,8 @@ func vpcToResource(ctx context.Context, diag diag.Diagnostics, s *tsClient.VPC,
 
 // Create creates a VPC shell
 func (r *vpcResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startSpan(ctx, "vpcResource.Create")
+	defer func() { endSpan(span, resp.Diagnostics) }()
 	tflog.Trace(ctx, "VpcResource.Create")
 	var plan vpcResourceModel
 
@@ -214,7 +233,7 @@ func (r *vpcResource) Create(ctx context.Context, req resource.CreateRequest, re
 		resp.Diagnostics.AddError(ErrVPCCreate, "CIDR is required")
 		return
 	}
-	vpc, err := r.client.CreateVPC(ctx, plan.Name.ValueString(), plan.CIDR.ValueString(), plan.RegionCode.ValueString())
+	vpc, err := projectClient(r.client, plan.ProjectID).CreateVPC(ctx, plan.Name.ValueString(), plan.CIDR.ValueString(), plan.RegionCode.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			fmt.Sprintf("Unable to Create Vpc %v", plan),
@@ -242,6 +261,8 @@ func (r *vpcResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 // Delete deletes a VPC shell
 func (r *vpcResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startSpan(ctx, "vpcResource.Delete")
+	defer func() { endSpan(span, resp.Diagnostics) }()
 	tflog.Trace(ctx, "VpcsResource.Delete")
 	var state vpcResourceModel
 	// // TODO: find a way to have this before automated test deletion
@@ -254,7 +275,11 @@ func (r *vpcResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 
 	tflog.Info(ctx, fmt.Sprintf("Deleting Vpc: %v", state.ID.ValueInt64()))
 
-	err := r.client.DeleteVPC(ctx, state.ID.ValueInt64())
+	err := projectClient(r.client, state.ProjectID).DeleteVPC(ctx, state.ID.ValueInt64())
+	if tsClient.IsNotFound(err) {
+		tflog.Info(ctx, fmt.Sprintf("Vpc already deleted: %v", state.ID.ValueInt64()))
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting Timescale Vpc",
@@ -266,6 +291,8 @@ func (r *vpcResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 
 // Update updates a VPC shell
 func (r *vpcResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startSpan(ctx, "vpcResource.Update")
+	defer func() { endSpan(span, resp.Diagnostics) }()
 	tflog.Trace(ctx, "VpcsResource.Update")
 	var plan, state vpcResourceModel
 	// Read Terraform plan data into the model
@@ -285,7 +312,7 @@ func (r *vpcResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	}
 
 	if !plan.Name.Equal(state.Name) {
-		if err := r.client.RenameVPC(ctx, state.ID.ValueInt64(), plan.Name.ValueString()); err != nil {
+		if err := projectClient(r.client, state.ProjectID).RenameVPC(ctx, state.ID.ValueInt64(), plan.Name.ValueString()); err != nil {
 			resp.Diagnostics.AddError(ErrVPCUpdate, err.Error())
 			return
 		}
@@ -294,8 +321,44 @@ func (r *vpcResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
 
+// ImportState imports a VPC by name or by ID, optionally in another project
+// than the provider's, see parseImportID.
 func (r *vpcResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	id, err := parseImportID(req.ID, importByName)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID",
+			fmt.Sprintf("Unable to import VPC: %s. Import IDs are <name>, name:<name> or id:<vpc_id>, optionally prefixed by project:<project_id>/.", err))
+		return
+	}
+	client := projectClient(r.client, types.StringValue(id.projectID))
+	var vpc *tsClient.VPC
+	if id.key == importByName {
+		vpc, err = client.GetVPCByName(ctx, id.value)
+	} else {
+		vpcID, parseErr := strconv.ParseInt(id.value, 10, 64)
+		if parseErr != nil {
+			resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Unable to import VPC: the ID of a VPC is a number, got %q.", id.value))
+			return
+		}
+		vpc, err = client.GetVPCByID(ctx, vpcID)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(clientErrorSummary(err), fmt.Sprintf("Unable to import VPC: %s", err))
+		return
+	}
+	// VPCs are looked up by ID in every project.
+	if vpc.ProjectID != "" && vpc.ProjectID != client.ProjectID() {
+		if id.projectID != "" {
+			resp.Diagnostics.AddError("Invalid Import ID",
+				fmt.Sprintf("Unable to import VPC: VPC %s belongs to project %s, not %s.", vpc.ID, vpc.ProjectID, id.projectID))
+			return
+		}
+		id.projectID = vpc.ProjectID
+	}
+	if id.projectID != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), id.projectID)...)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), vpc.Name)...)
 }
 
 // Configure adds the provider configured client to the data source.
@@ -304,11 +367,11 @@ func (r *vpcResource) Configure(ctx context.Context, req resource.ConfigureReque
 	if req.ProviderData == nil {
 		return
 	}
-	client, ok := req.ProviderData.(*tsClient.Client)
+	client, ok := req.ProviderData.(tsClient.API)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *tsClient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected tsClient.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -332,9 +395,13 @@ func (r *vpcResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				},
 			},
 			"project_id": schema.StringAttribute{
-				Computed: true,
+				Description:         "project id",
+				MarkdownDescription: "ID of the project of this VPC. Defaults to the project of the provider. Changing it creates a new VPC.",
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"cidr": schema.StringAttribute{
@@ -1,11 +1,25 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/require"
+
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
+	"github.com/timescale/terraform-provider-timescale/client/fake"
+	"github.com/timescale/terraform-provider-timescale/internal/testserver"
 )
 
 var (
@@ -17,7 +31,7 @@ var (
 func TestVPCResource_Default_Success(t *testing.T) {
 	// Test resource creation succeeds
 	resource.ParallelTest(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: cassetteProviderFactories(t),
 		PreCheck:                 func() { testAccPreCheck(t) },
 		Steps: []resource.TestStep{
 			// Create the VPC
@@ -56,7 +70,7 @@ func TestVPCResource_Default_Success(t *testing.T) {
 
 func TestVPCResource_Import(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: cassetteProviderFactories(t),
 		PreCheck:                 func() { testAccPreCheck(t) },
 		Steps: []resource.TestStep{
 			// Create the VPC to import
@@ -80,3 +94,215 @@ func TestVPCResource_Import(t *testing.T) {
 		},
 	})
 }
+
+func newFakeVPCResource(t *testing.T, client tsClient.API) (*vpcResource, resourceschema.Schema) {
+	t.Helper()
+	r := &vpcResource{client: client}
+	var resp fwresource.SchemaResponse
+	r.Schema(context.Background(), fwresource.SchemaRequest{}, &resp)
+	requireNoErrors(t, resp.Diagnostics)
+	return r, resp.Schema
+}
+
+func newVPCPlan(name, cidr, regionCode string) vpcResourceModel {
+	return vpcResourceModel{
+		ID:                 types.Int64Unknown(),
+		ProvisionedID:      types.StringUnknown(),
+		ProjectID:          types.StringUnknown(),
+		CIDR:               types.StringValue(cidr),
+		Name:               types.StringValue(name),
+		RegionCode:         types.StringValue(regionCode),
+		Status:             types.StringUnknown(),
+		ErrorMessage:       types.StringUnknown(),
+		Created:            types.StringUnknown(),
+		Updated:            types.StringUnknown(),
+		PeeringConnections: types.ListUnknown(PeeringConnectionsType),
+	}
+}
+
+func TestVPCResource_Fake_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+	r, s := newFakeVPCResource(t, client)
+
+	createResp := fwresource.CreateResponse{State: emptyState(t, s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan{Schema: s, Raw: tfValue(t, s, newVPCPlan("vpc-1", "10.0.0.0/21", "us-east-1"))}}, &createResp)
+	requireNoErrors(t, createResp.Diagnostics)
+	var state vpcResourceModel
+	requireNoErrors(t, createResp.State.Get(ctx, &state))
+	require.Equal(t, "vpc-1", state.Name.ValueString())
+	require.Equal(t, fake.DefaultProjectID, state.ProjectID.ValueString())
+	require.NotZero(t, state.ID.ValueInt64())
+
+	plan := state
+	plan.Name = types.StringValue("vpc-renamed")
+	prior := tfsdk.State{Schema: s, Raw: tfValue(t, s, state)}
+	updateResp := fwresource.UpdateResponse{State: prior}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: tfsdk.Plan{Schema: s, Raw: tfValue(t, s, plan)}, State: prior}, &updateResp)
+	requireNoErrors(t, updateResp.Diagnostics)
+	requireNoErrors(t, updateResp.State.Get(ctx, &state))
+	require.Equal(t, "vpc-renamed", state.Name.ValueString())
+
+	prior = tfsdk.State{Schema: s, Raw: tfValue(t, s, state)}
+	readResp := fwresource.ReadResponse{State: prior}
+	r.Read(ctx, fwresource.ReadRequest{State: prior}, &readResp)
+	requireNoErrors(t, readResp.Diagnostics)
+	requireNoErrors(t, readResp.State.Get(ctx, &state))
+	require.Equal(t, "vpc-renamed", state.Name.ValueString())
+
+	deleteResp := fwresource.DeleteResponse{}
+	r.Delete(ctx, fwresource.DeleteRequest{State: prior}, &deleteResp)
+	requireNoErrors(t, deleteResp.Diagnostics)
+	vpcs, err := client.GetVPCs(ctx)
+	require.NoError(t, err)
+	require.Empty(t, vpcs)
+}
+
+func TestVPCResource_Fake_ReadRemovesVanishedVPC(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+	r, s := newFakeVPCResource(t, client)
+
+	createResp := fwresource.CreateResponse{State: emptyState(t, s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan{Schema: s, Raw: tfValue(t, s, newVPCPlan("vanished", "10.0.0.0/21", "us-east-1"))}}, &createResp)
+	requireNoErrors(t, createResp.Diagnostics)
+	var state vpcResourceModel
+	requireNoErrors(t, createResp.State.Get(ctx, &state))
+	client.RemoveVPC(state.ID.ValueInt64())
+
+	readResp := fwresource.ReadResponse{State: createResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: createResp.State}, &readResp)
+	require.False(t, readResp.Diagnostics.HasError())
+	require.Len(t, readResp.Diagnostics.Warnings(), 1)
+	require.True(t, readResp.State.Raw.IsNull())
+}
+
+func TestVPCResource_Fake_ReadFindsRenamedVPC(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+	r, s := newFakeVPCResource(t, client)
+
+	createResp := fwresource.CreateResponse{State: emptyState(t, s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan{Schema: s, Raw: tfValue(t, s, newVPCPlan("original", "10.0.0.0/21", "us-east-1"))}}, &createResp)
+	requireNoErrors(t, createResp.Diagnostics)
+	var state vpcResourceModel
+	requireNoErrors(t, createResp.State.Get(ctx, &state))
+	require.NoError(t, client.RenameVPC(ctx, state.ID.ValueInt64(), "renamed in the console"))
+
+	// The VPC is found by its ID, and kept in the state.
+	readResp := fwresource.ReadResponse{State: createResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: createResp.State}, &readResp)
+	requireNoErrors(t, readResp.Diagnostics)
+	require.Empty(t, readResp.Diagnostics.Warnings())
+	var refreshed vpcResourceModel
+	requireNoErrors(t, readResp.State.Get(ctx, &refreshed))
+	require.Equal(t, state.ID, refreshed.ID)
+	require.Equal(t, "renamed in the console", refreshed.Name.ValueString())
+}
+
+func TestVPCResource_ReadRemovesDeletedVPC(t *testing.T) {
+	ctx := context.Background()
+	// Reads go through the client, to which the test server reports deleted
+	// VPCs with a NOT_FOUND error, whether looked up by name or by ID.
+	server := httptest.NewServer(testserver.New(testserver.Options{}))
+	t.Cleanup(server.Close)
+	client := tsClient.New("project", tsClient.WithToken("token"), tsClient.WithURL(server.URL))
+	r, s := newFakeVPCResource(t, client)
+
+	createResp := fwresource.CreateResponse{State: emptyState(t, s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan{Schema: s, Raw: tfValue(t, s, newVPCPlan("deleted", "10.0.0.0/21", "us-east-1"))}}, &createResp)
+	requireNoErrors(t, createResp.Diagnostics)
+	var state vpcResourceModel
+	requireNoErrors(t, createResp.State.Get(ctx, &state))
+	require.NoError(t, client.DeleteVPC(ctx, state.ID.ValueInt64()))
+
+	readResp := fwresource.ReadResponse{State: createResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: createResp.State}, &readResp)
+	require.False(t, readResp.Diagnostics.HasError())
+	require.Len(t, readResp.Diagnostics.Warnings(), 1)
+	require.Equal(t, "VPC Not Found", readResp.Diagnostics.Warnings()[0].Summary())
+	require.Contains(t, readResp.Diagnostics.Warnings()[0].Detail(), fmt.Sprintf("VPC deleted (%d) no longer exists and has been removed from the state", state.ID.ValueInt64()))
+	require.True(t, readResp.State.Raw.IsNull())
+}
+
+func TestVPCResource_Fake_OtherProject(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+	r, s := newFakeVPCResource(t, client)
+
+	plan := newVPCPlan("vpc-1", "10.0.0.0/21", "us-east-1")
+	plan.ProjectID = types.StringValue("other")
+	createResp := fwresource.CreateResponse{State: emptyState(t, s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan{Schema: s, Raw: tfValue(t, s, plan)}}, &createResp)
+	requireNoErrors(t, createResp.Diagnostics)
+	var state vpcResourceModel
+	requireNoErrors(t, createResp.State.Get(ctx, &state))
+	require.Equal(t, "other", state.ProjectID.ValueString())
+	vpcs, err := client.Project("other").GetVPCs(ctx)
+	require.NoError(t, err)
+	require.Len(t, vpcs, 1)
+	vpcs, err = client.GetVPCs(ctx)
+	require.NoError(t, err)
+	require.Empty(t, vpcs)
+
+	// A VPC of another project is imported with its project.
+	importResp := fwresource.ImportStateResponse{State: emptyState(t, s)}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: "other/vpc-1"}, &importResp)
+	requireNoErrors(t, importResp.Diagnostics)
+	readResp := fwresource.ReadResponse{State: importResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: importResp.State}, &readResp)
+	requireNoErrors(t, readResp.Diagnostics)
+	requireNoErrors(t, readResp.State.Get(ctx, &state))
+	require.Equal(t, "other", state.ProjectID.ValueString())
+	require.Equal(t, "10.0.0.0/21", state.CIDR.ValueString())
+}
+
+func TestVPCResource_Fake_ImportState(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+	r, s := newFakeVPCResource(t, client)
+	vpc, err := client.CreateVPC(ctx, "vpc-1", "10.0.0.0/21", "us-east-1")
+	require.NoError(t, err)
+	other, err := client.Project("other").CreateVPC(ctx, "vpc-2", "10.0.8.0/21", "us-east-1")
+	require.NoError(t, err)
+
+	importState := func(id string) (vpcResourceModel, diag.Diagnostics) {
+		resp := fwresource.ImportStateResponse{State: emptyState(t, s)}
+		r.ImportState(ctx, fwresource.ImportStateRequest{ID: id}, &resp)
+		if resp.Diagnostics.HasError() {
+			return vpcResourceModel{}, resp.Diagnostics
+		}
+		readResp := fwresource.ReadResponse{State: resp.State}
+		r.Read(ctx, fwresource.ReadRequest{State: resp.State}, &readResp)
+		var state vpcResourceModel
+		readResp.Diagnostics.Append(readResp.State.Get(ctx, &state)...)
+		return state, readResp.Diagnostics
+	}
+	for id, want := range map[string]*tsClient.VPC{
+		"vpc-1":                        vpc,
+		"name:vpc-1":                   vpc,
+		"id:" + vpc.ID:                 vpc,
+		"other/vpc-2":                  other,
+		"project:other/name:vpc-2":     other,
+		"project:other/id:" + other.ID: other,
+		// VPCs are found by ID in any project.
+		"id:" + other.ID: other,
+	} {
+		state, diags := importState(id)
+		requireNoErrors(t, diags)
+		require.Equal(t, want.ID, strconv.FormatInt(state.ID.ValueInt64(), 10), id)
+		require.Equal(t, want.Name, state.Name.ValueString(), id)
+		require.Equal(t, want.ProjectID, state.ProjectID.ValueString(), id)
+	}
+
+	for id, detail := range map[string]string{
+		"name:missing":               "not found",
+		"id:vpc-1":                   "the ID of a VPC is a number",
+		"project:other/id:" + vpc.ID: "belongs to project " + fake.DefaultProjectID,
+		"project:other/vpc-2":        "expected name:<name> or id:<id>",
+	} {
+		_, diags := importState(id)
+		require.True(t, diags.HasError(), id)
+		require.Contains(t, diags.Errors()[0].Detail(), detail, id)
+	}
+}
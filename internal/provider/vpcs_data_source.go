@@ -8,9 +8,10 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	tsClient "github.com/timescale/terraform-provider-timescale/internal/client"
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -51,12 +52,13 @@ var (
 
 // vpcsDataSource is the data source implementation.
 type vpcsDataSource struct {
-	client *tsClient.Client
+	client tsClient.API
 }
 
 // vpcsDataSourceModel maps the data source schema data.
 type vpcsDataSourceModel struct {
-	Vpcs []vpcDSModel `tfsdk:"vpcs"`
+	ProjectID types.String `tfsdk:"project_id"`
+	Vpcs      []vpcDSModel `tfsdk:"vpcs"`
 	// following is a placeholder, required by terraform to run test suite
 	ID types.String `tfsdk:"id"`
 }
@@ -96,10 +98,19 @@ func (d *vpcsDataSource) Metadata(_ context.Context, req datasource.MetadataRequ
 }
 
 // Read refreshes the Terraform state with the latest data.
-func (d *vpcsDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+func (d *vpcsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := startSpan(ctx, "vpcsDataSource.Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
 	var state vpcsDataSourceModel
 
-	vpcs, err := d.client.GetVPCs(ctx)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("project_id"), &state.ProjectID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	client := projectClient(d.client, state.ProjectID)
+	state.ProjectID = types.StringValue(client.ProjectID())
+
+	vpcs, err := client.GetVPCs(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Vpcs",
@@ -166,7 +177,7 @@ func (d *vpcsDataSource) Configure(_ context.Context, req datasource.ConfigureRe
 		return
 	}
 
-	d.client = req.ProviderData.(*tsClient.Client)
+	d.client = req.ProviderData.(tsClient.API)
 }
 
 // Schema defines the schema for the data source.
@@ -176,6 +187,12 @@ func (d *vpcsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, r
 			"id": schema.StringAttribute{
 				Computed: true,
 			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the project to list the VPCs of. Defaults to the project of the provider.",
+				Description:         "project id",
+				Optional:            true,
+				Computed:            true,
+			},
 			"vpcs": schema.ListNestedAttribute{
 				Computed: true,
 				NestedObject: schema.NestedAttributeObject{
@@ -0,0 +1,402 @@
+// Package testserver implements a local stand-in for the Timescale GraphQL
+// API. It understands the operations sent by the client, keeps projects in
+// memory and lets tests slow down or break individual operations.
+package testserver
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
+	"github.com/timescale/terraform-provider-timescale/client/fake"
+)
+
+// Options configures a Server.
+type Options struct {
+	// AccessKey and SecretKey are the only client credentials accepted when
+	// set. Any credentials are accepted otherwise.
+	AccessKey string
+	SecretKey string
+	// TokenLifetime is the lifetime of the issued JWTs. Defaults to an hour.
+	TokenLifetime time.Duration
+	// TransitionDelay is how long a service stays in each status of the
+	// QUEUED, CONFIGURING, READY lifecycle.
+	TransitionDelay time.Duration
+	// StaleReadyDelay is how long a READY service keeps being reported as it
+	// was before a change, until the change starts being applied.
+	StaleReadyDelay time.Duration
+	// Products is returned by GetProducts.
+	Products []*tsClient.Product
+	// Subscriptions serves the WatchServiceStatus subscription over
+	// WebSockets. Clients poll services otherwise.
+	Subscriptions bool
+}
+
+// Fault replaces the normal handling of an operation.
+type Fault struct {
+	// Delay is waited before answering, e.g. to trigger client timeouts.
+	Delay time.Duration
+	// StatusCode, when set, is returned without a GraphQL body.
+	StatusCode int
+	// RetryAfter is sent as the Retry-After header, in seconds.
+	RetryAfter int
+	// Message and Code, when set, are returned as a GraphQL error.
+	Message string
+	Code    string
+	// Times is how many requests the fault applies to. Zero means once.
+	Times int
+	// AfterHandling applies the fault once the operation is carried out, as
+	// when the response is lost on its way back.
+	AfterHandling bool
+}
+
+// Server is an http.Handler serving the Timescale GraphQL API.
+type Server struct {
+	opts Options
+
+	mu       sync.Mutex
+	projects map[string]*fake.Client
+	tokens   map[string]bool
+	faults   map[string][]*Fault
+	requests map[string]int
+	tokenSeq int
+}
+
+// New returns a server with no projects. Projects are created on first use.
+func New(opts Options) *Server {
+	if opts.TokenLifetime == 0 {
+		opts.TokenLifetime = time.Hour
+	}
+	return &Server{
+		opts:     opts,
+		projects: map[string]*fake.Client{},
+		tokens:   map[string]bool{},
+		faults:   map[string][]*Fault{},
+		requests: map[string]int{},
+	}
+}
+
+// Start runs the server on a local port until the returned server is closed.
+func Start(opts Options) (*Server, *httptest.Server) {
+	s := New(opts)
+	return s, httptest.NewServer(s)
+}
+
+// InjectFault makes the next requests for the operation fail as described.
+func (s *Server) InjectFault(operationName string, fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fault.Times <= 0 {
+		fault.Times = 1
+	}
+	s.faults[operationName] = append(s.faults[operationName], &fault)
+}
+
+// Requests returns how many requests were received for an operation.
+func (s *Server) Requests(operationName string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[operationName]
+}
+
+// Project returns the in-memory state of a project, creating it if needed.
+func (s *Server) Project(projectID string) *fake.Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.project(projectID)
+}
+
+// project returns a project. The caller must hold mu.
+func (s *Server) project(projectID string) *fake.Client {
+	p, ok := s.projects[projectID]
+	if !ok {
+		// Projects are siblings, so that services can be forked across
+		// projects and IDs are unique in the server.
+		for _, sibling := range s.projects {
+			p = sibling.Project(projectID)
+			break
+		}
+		if p == nil {
+			p = fake.New(projectID)
+		}
+		p.TransitionDelay = s.opts.TransitionDelay
+		if p.TransitionDelay == 0 {
+			// Services polled through HTTP must not depend on the number
+			// of requests, which retries make unpredictable.
+			p.TransitionDelay = time.Nanosecond
+		}
+		p.StaleReadyDelay = s.opts.StaleReadyDelay
+		p.Products = s.opts.Products
+		s.projects[projectID] = p
+	}
+	return p
+}
+
+type request struct {
+	OperationName string    `json:"operationName"`
+	Query         string    `json:"query"`
+	Variables     variables `json:"variables"`
+}
+
+// variables holds the variables of every supported operation.
+type variables struct {
+	ProjectID              string               `json:"projectId"`
+	ServiceID              string               `json:"serviceId"`
+	Name                   string               `json:"name"`
+	NewName                string               `json:"newName"`
+	RegionCode             string               `json:"regionCode"`
+	CIDR                   string               `json:"cidr"`
+	VPCID                  json.Number          `json:"vpcId"`
+	ForgeVPCID             json.Number          `json:"forgeVpcId"`
+	ResourceConfig         map[string]string    `json:"resourceConfig"`
+	Config                 map[string]string    `json:"config"`
+	ForkConfig             *tsClient.ForkConfig `json:"forkConfig"`
+	EnableConnectionPooler bool                 `json:"enableConnectionPooler"`
+	Enable                 bool                 `json:"enable"`
+	ReplicaCount           int                  `json:"replicaCount"`
+	AccessKey              string               `json:"accessKey"`
+	SecretKey              string               `json:"secretKey"`
+}
+
+type response struct {
+	Data   map[string]any  `json:"data,omitempty"`
+	Errors tsClient.Errors `json:"errors,omitempty"`
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Subscriptions && websocket.IsWebSocketUpgrade(r) {
+		s.serveSubscription(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, response{Errors: tsClient.Errors{{Message: err.Error()}}})
+		return
+	}
+
+	fault := s.recordRequest(req.OperationName)
+	if fault != nil && !fault.AfterHandling && applyFault(w, r, fault) {
+		return
+	}
+
+	if req.OperationName != "GetJWTForClientCredentials" && !s.authorized(r) {
+		writeJSON(w, http.StatusOK, response{Errors: tsClient.Errors{newError(tsClient.CodeUnauthenticated, "invalid or expired token")}})
+		return
+	}
+
+	field, data, err := s.handle(r.Context(), req)
+	if fault != nil && fault.AfterHandling && applyFault(w, r, fault) {
+		return
+	}
+	if err != nil {
+		var gqlErr *tsClient.Error
+		if !errors.As(err, &gqlErr) {
+			gqlErr = &tsClient.Error{Message: err.Error()}
+		}
+		gqlErr.Path = []any{field}
+		writeJSON(w, http.StatusOK, response{Data: map[string]any{field: nil}, Errors: tsClient.Errors{gqlErr}})
+		return
+	}
+	writeJSON(w, http.StatusOK, response{Data: map[string]any{field: data}})
+}
+
+// applyFault waits for the delay of a fault and writes its response, if
+// any. It returns whether the request is answered.
+func applyFault(w http.ResponseWriter, r *http.Request, fault *Fault) bool {
+	if fault.Delay > 0 {
+		select {
+		case <-time.After(fault.Delay):
+		case <-r.Context().Done():
+			return true
+		}
+	}
+	if fault.StatusCode != 0 {
+		if fault.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(fault.RetryAfter))
+		}
+		http.Error(w, http.StatusText(fault.StatusCode), fault.StatusCode)
+		return true
+	}
+	if fault.Message != "" || fault.Code != "" {
+		writeJSON(w, http.StatusOK, response{Errors: tsClient.Errors{newError(fault.Code, fault.Message)}})
+		return true
+	}
+	return false
+}
+
+// recordRequest counts a request and returns the fault to apply to it, if
+// any.
+func (s *Server) recordRequest(operationName string) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[operationName]++
+	faults := s.faults[operationName]
+	if len(faults) == 0 {
+		return nil
+	}
+	fault := faults[0]
+	fault.Times--
+	if fault.Times <= 0 {
+		s.faults[operationName] = faults[1:]
+	}
+	return fault
+}
+
+// handle runs an operation and returns the name of the top-level field of
+// the response with its value.
+func (s *Server) handle(ctx context.Context, req request) (string, any, error) {
+	v := req.Variables
+	switch req.OperationName {
+	case "GetJWTForClientCredentials":
+		token, err := s.issueToken(v.AccessKey, v.SecretKey)
+		return "getJWTForClientCredentials", token, err
+
+	case "CreateService":
+		resp, err := s.Project(v.ProjectID).CreateService(ctx, tsClient.CreateServiceRequest{
+			Name:                   v.Name,
+			MilliCPU:               v.ResourceConfig["milliCPU"],
+			MemoryGB:               v.ResourceConfig["memoryGB"],
+			StorageGB:              v.ResourceConfig["storageGB"],
+			ReplicaCount:           v.ResourceConfig["replicaCount"],
+			RegionCode:             v.RegionCode,
+			VpcID:                  toInt64(v.VPCID),
+			ForkConfig:             v.ForkConfig,
+			EnableConnectionPooler: v.EnableConnectionPooler,
+		})
+		return "createService", resp, err
+	case "RenameService":
+		return "renameService", true, s.Project(v.ProjectID).RenameService(ctx, v.ServiceID, v.NewName)
+	case "SetReplicaCount":
+		return "setReplicaCount", true, s.Project(v.ProjectID).SetReplicaCount(ctx, v.ServiceID, v.ReplicaCount)
+	case "ResizeInstance":
+		return "resizeInstance", true, s.Project(v.ProjectID).ResizeInstance(ctx, v.ServiceID, tsClient.ResourceConfig{
+			MilliCPU: v.Config["milliCPU"],
+			MemoryGB: v.Config["memoryGB"],
+		})
+	case "ToggleConnectionPooler":
+		return "toggleConnectionPooler", true, s.Project(v.ProjectID).ToggleConnectionPooler(ctx, v.ServiceID, v.Enable)
+	case "GetService":
+		service, err := s.Project(v.ProjectID).GetService(ctx, v.ServiceID)
+		return "getService", service, err
+	case "GetServiceRecoveryWindow":
+		window, err := s.Project(v.ProjectID).GetRecoveryWindow(ctx, v.ServiceID)
+		return "getServiceRecoveryWindow", window, err
+	case "GetAllServices":
+		services, err := s.Project(v.ProjectID).GetAllServices(ctx)
+		return "getAllServices", services, err
+	case "DeleteService":
+		service, err := s.Project(v.ProjectID).DeleteService(ctx, v.ServiceID)
+		return "deleteService", service, err
+
+	case "GetAllVPCs":
+		vpcs, err := s.Project(v.ProjectID).GetVPCs(ctx)
+		return "getAllVPCs", vpcs, err
+	case "GetVPCByName":
+		vpc, err := s.Project(v.ProjectID).GetVPCByName(ctx, v.Name)
+		return "getVPCByName", vpc, err
+	case "GetVPC":
+		vpc, err := s.vpcByID(ctx, toInt64(v.VPCID))
+		return "getVpc", vpc, err
+	case "AttachServiceToVPC":
+		return "attachServiceToVpc", true, s.Project(v.ProjectID).AttachServiceToVPC(ctx, v.ServiceID, toInt64(v.VPCID))
+	case "DetachServiceFromVPC":
+		return "detachServiceFromVpc", true, s.Project(v.ProjectID).DetachServiceFromVPC(ctx, v.ServiceID, toInt64(v.VPCID))
+	case "CreateVPC":
+		vpc, err := s.Project(v.ProjectID).CreateVPC(ctx, v.Name, v.CIDR, v.RegionCode)
+		return "createVpc", vpc, err
+	case "RenameVPC":
+		return "renameVpc", true, s.Project(v.ProjectID).RenameVPC(ctx, toInt64(v.ForgeVPCID), v.NewName)
+	case "DeleteVPC":
+		return "deleteVpc", true, s.Project(v.ProjectID).DeleteVPC(ctx, toInt64(v.VPCID))
+
+	case "GetProducts":
+		products, err := s.Project(v.ProjectID).GetProducts(ctx)
+		return "products", products, err
+	}
+	return "", nil, newError("UNKNOWN_OPERATION", fmt.Sprintf("unknown operation %q", req.OperationName))
+}
+
+// vpcByID looks a VPC up in every project, since the query is not scoped to
+// one.
+func (s *Server) vpcByID(ctx context.Context, vpcID int64) (*tsClient.VPC, error) {
+	s.mu.Lock()
+	projects := make([]*fake.Client, 0, len(s.projects))
+	for _, p := range s.projects {
+		projects = append(projects, p)
+	}
+	s.mu.Unlock()
+	for _, p := range projects {
+		if vpc, err := p.GetVPCByID(ctx, vpcID); err == nil {
+			return vpc, nil
+		}
+	}
+	return nil, newError(tsClient.CodeNotFound, fmt.Sprintf("vpc %d not found", vpcID))
+}
+
+func (s *Server) issueToken(accessKey, secretKey string) (string, error) {
+	if s.opts.AccessKey != "" && (accessKey != s.opts.AccessKey || secretKey != s.opts.SecretKey) {
+		return "", newError(tsClient.CodeUnauthenticated, "invalid client credentials")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokenSeq++
+	claims, err := json.Marshal(map[string]any{
+		"exp": time.Now().Add(s.opts.TokenLifetime).Unix(),
+		"jti": s.tokenSeq,
+	})
+	if err != nil {
+		return "", err
+	}
+	token := "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString(claims) + ".testserver"
+	s.tokens[token] = true
+	return token, nil
+}
+
+// authorized reports whether the request carries a token issued by the
+// server, or any token when no credentials are configured.
+func (s *Server) authorized(r *http.Request) bool {
+	if s.opts.AccessKey == "" {
+		return true
+	}
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if len(header) <= len(prefix) {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[header[len(prefix):]]
+}
+
+func newError(code, message string) *tsClient.Error {
+	err := &tsClient.Error{Message: message}
+	if code != "" {
+		err.Extensions = map[string]any{"code": code}
+	}
+	return err
+}
+
+func toInt64(n json.Number) int64 {
+	v, _ := n.Int64()
+	return v
+}
+
+func writeJSON(w http.ResponseWriter, status int, resp response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,195 @@
+package testserver
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
+)
+
+func newTestClient(t *testing.T, opts Options, clientOpts ...tsClient.Option) (*Server, *tsClient.Client) {
+	t.Helper()
+	s, server := Start(opts)
+	t.Cleanup(server.Close)
+	c := tsClient.New("project", append([]tsClient.Option{
+		tsClient.WithURL(server.URL),
+		tsClient.WithRetryPolicy(tsClient.RetryPolicy{MaxAttempts: 3, MaxWait: 10 * time.Millisecond}),
+	}, clientOpts...)...)
+	require.NoError(t, tsClient.JWTFromCC(context.Background(), c, opts.AccessKey, opts.SecretKey))
+	return s, c
+}
+
+func TestServer_ServiceLifecycle(t *testing.T) {
+	_, c := newTestClient(t, Options{TransitionDelay: 10 * time.Millisecond})
+	ctx := context.Background()
+
+	vpc, err := c.CreateVPC(ctx, "vpc", "10.0.0.0/16", "us-east-1")
+	require.NoError(t, err)
+	created, err := c.CreateService(ctx, tsClient.CreateServiceRequest{
+		Name:       "service",
+		MilliCPU:   "500",
+		MemoryGB:   "2",
+		RegionCode: "us-east-1",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.InitialPassword)
+	require.Equal(t, "QUEUED", created.Service.Status)
+
+	require.Eventually(t, func() bool {
+		service, err := c.GetService(ctx, created.Service.ID)
+		require.NoError(t, err)
+		return service.Status == "READY"
+	}, time.Second, 5*time.Millisecond)
+
+	vpcID := mustParseInt(t, vpc.ID)
+	byID, err := c.GetVPCByID(ctx, vpcID)
+	require.NoError(t, err)
+	require.Equal(t, "vpc", byID.Name)
+	_, err = c.GetVPCByID(ctx, vpcID+1000)
+	require.True(t, tsClient.IsNotFound(err))
+	window, err := c.GetRecoveryWindow(ctx, created.Service.ID)
+	require.NoError(t, err)
+	earliest, err := time.Parse(time.RFC3339, window.Earliest)
+	require.NoError(t, err)
+	ok, err := window.Contains(earliest)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = window.Contains(earliest.Add(-time.Hour))
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NoError(t, c.AttachServiceToVPC(ctx, created.Service.ID, vpcID))
+	require.NoError(t, c.RenameService(ctx, created.Service.ID, "renamed"))
+	services, err := c.GetAllServices(ctx)
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	require.Equal(t, "renamed", services[0].Name)
+
+	_, err = c.DeleteService(ctx, created.Service.ID)
+	require.NoError(t, err)
+	_, err = c.GetService(ctx, created.Service.ID)
+	require.True(t, tsClient.IsNotFound(err))
+}
+
+func TestServer_Faults(t *testing.T) {
+	s, c := newTestClient(t, Options{})
+	ctx := context.Background()
+
+	s.InjectFault("GetAllServices", Fault{StatusCode: http.StatusServiceUnavailable, Times: 2})
+	_, err := c.GetAllServices(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, s.Requests("GetAllServices"))
+
+	s.InjectFault("CreateVPC", Fault{Code: tsClient.CodeConflict, Message: "already exists"})
+	_, err = c.CreateVPC(ctx, "vpc", "10.0.0.0/16", "us-east-1")
+	require.True(t, tsClient.IsConflict(err))
+}
+
+func TestServer_RejectsUnknownCredentials(t *testing.T) {
+	_, server := Start(Options{AccessKey: "access", SecretKey: "secret"})
+	t.Cleanup(server.Close)
+
+	c := tsClient.New("project", tsClient.WithURL(server.URL))
+	require.True(t, tsClient.IsUnauthorized(tsClient.JWTFromCC(context.Background(), c, "access", "wrong")))
+	_, err := c.GetProducts(context.Background())
+	require.True(t, tsClient.IsUnauthorized(err))
+
+	require.NoError(t, tsClient.JWTFromCC(context.Background(), c, "access", "secret"))
+	_, err = c.GetProducts(context.Background())
+	require.NoError(t, err)
+}
+
+func mustParseInt(t *testing.T, s string) int64 {
+	t.Helper()
+	n, err := strconv.ParseInt(s, 10, 64)
+	require.NoError(t, err)
+	return n
+}
+
+func TestServer_LostCreateResponse(t *testing.T) {
+	s, c := newTestClient(t, Options{})
+	ctx := context.Background()
+	request := tsClient.CreateServiceRequest{Name: "ingest", MilliCPU: "500", MemoryGB: "2", RegionCode: "us-east-1"}
+
+	// The service is created but the response is lost: the client finds and
+	// adopts it instead of leaving it behind.
+	s.InjectFault("CreateService", Fault{StatusCode: http.StatusGatewayTimeout, AfterHandling: true})
+	created, err := c.CreateService(ctx, request)
+	require.NoError(t, err)
+	require.True(t, created.Adopted)
+	services, err := c.GetAllServices(ctx)
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	require.Equal(t, services[0].ID, created.Service.ID)
+	require.Equal(t, 1, s.Requests("CreateService"))
+
+	// Failures the API answers are not reconciled.
+	s.InjectFault("CreateService", Fault{Code: tsClient.CodeConflict, Message: "quota exceeded"})
+	_, err = c.CreateService(ctx, request)
+	require.True(t, tsClient.IsConflict(err))
+	require.Equal(t, 2, s.Requests("GetAllServices"))
+}
+
+func TestServer_Subscriptions(t *testing.T) {
+	for _, subscriptions := range []bool{true, false} {
+		t.Run(strconv.FormatBool(subscriptions), func(t *testing.T) {
+			s, c := newTestClient(t, Options{
+				AccessKey:       "access",
+				SecretKey:       "secret",
+				TransitionDelay: 20 * time.Millisecond,
+				Subscriptions:   subscriptions,
+			}, tsClient.WithSubscriptions(true))
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			created, err := c.CreateService(ctx, tsClient.CreateServiceRequest{Name: "service", MilliCPU: "500", MemoryGB: "2", RegionCode: "us-east-1"})
+			require.NoError(t, err)
+
+			policy := tsClient.PollPolicy{MinInterval: time.Millisecond, MaxInterval: time.Millisecond}
+			var statuses []string
+			for update := range tsClient.NewStatusWatcher(c, policy).Watch(ctx, created.Service.ID) {
+				require.NoError(t, update.Err)
+				statuses = append(statuses, update.Service.Status)
+				if update.Service.Status == "READY" {
+					break
+				}
+			}
+			require.Equal(t, "QUEUED", statuses[0])
+			require.Equal(t, "READY", statuses[len(statuses)-1])
+			if subscriptions {
+				require.Equal(t, 1, s.Requests("WatchServiceStatus"))
+				require.Equal(t, 1, s.Requests("GetService"))
+			} else {
+				require.Zero(t, s.Requests("WatchServiceStatus"))
+				require.Greater(t, s.Requests("GetService"), 2)
+			}
+		})
+	}
+}
+
+func TestServer_StaleReady(t *testing.T) {
+	_, c := newTestClient(t, Options{TransitionDelay: 10 * time.Millisecond, StaleReadyDelay: 100 * time.Millisecond})
+	ctx := context.Background()
+	created, err := c.CreateService(ctx, tsClient.CreateServiceRequest{Name: "service", MilliCPU: "500", MemoryGB: "2", RegionCode: "us-east-1"})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		service, err := c.GetService(ctx, created.Service.ID)
+		return err == nil && service.Status == "READY"
+	}, time.Second, 5*time.Millisecond)
+
+	// The service is reported as it was before the resize for a while.
+	require.NoError(t, c.ResizeInstance(ctx, created.Service.ID, tsClient.ResourceConfig{MilliCPU: "1000", MemoryGB: "4"}))
+	service, err := c.GetService(ctx, created.Service.ID)
+	require.NoError(t, err)
+	require.Equal(t, "READY", service.Status)
+	require.EqualValues(t, 500, service.Resources[0].Spec.MilliCPU)
+
+	require.Eventually(t, func() bool {
+		service, err = c.GetService(ctx, created.Service.ID)
+		require.NoError(t, err)
+		return service.Resources[0].Spec.MilliCPU == 1000
+	}, time.Second, 5*time.Millisecond)
+}
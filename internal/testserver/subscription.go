@@ -0,0 +1,106 @@
+package testserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
+)
+
+// subscriptionPollInterval is how often a subscribed service is checked for
+// status changes.
+const subscriptionPollInterval = 5 * time.Millisecond
+
+// subscriptionMessage is a message of the graphql-transport-ws protocol.
+type subscriptionMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+var upgrader = websocket.Upgrader{
+	Subprotocols: []string{"graphql-transport-ws"},
+}
+
+// serveSubscription serves the WatchServiceStatus subscription over a
+// WebSocket, pushing the state of the service whenever its status changes.
+func (s *Server) serveSubscription(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		http.Error(w, "invalid or expired token", http.StatusUnauthorized)
+		return
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var msg subscriptionMessage
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "connection_init" {
+		return
+	}
+	if err := conn.WriteJSON(subscriptionMessage{Type: "connection_ack"}); err != nil {
+		return
+	}
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "subscribe" {
+		return
+	}
+	var req request
+	if err := json.Unmarshal(msg.Payload, &req); err != nil {
+		return
+	}
+	s.recordRequest(req.OperationName)
+	if req.OperationName != "WatchServiceStatus" {
+		errs, _ := json.Marshal(tsClient.Errors{newError("UNKNOWN_OPERATION", "unknown subscription "+req.OperationName)})
+		_ = conn.WriteJSON(subscriptionMessage{ID: msg.ID, Type: "error", Payload: errs})
+		return
+	}
+
+	// The client completes the subscription or closes the connection.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			var msg subscriptionMessage
+			if err := conn.ReadJSON(&msg); err != nil || msg.Type == "complete" {
+				return
+			}
+		}
+	}()
+
+	project := s.Project(req.Variables.ProjectID)
+	status := ""
+	ticker := time.NewTicker(subscriptionPollInterval)
+	defer ticker.Stop()
+	for {
+		service, err := project.GetService(r.Context(), req.Variables.ServiceID)
+		if err != nil {
+			_ = writeNext(conn, msg.ID, response{Errors: tsClient.Errors{{Message: err.Error()}}})
+			_ = conn.WriteJSON(subscriptionMessage{ID: msg.ID, Type: "complete"})
+			return
+		}
+		if service.Status != status {
+			status = service.Status
+			if err := writeNext(conn, msg.ID, response{Data: map[string]any{"serviceStatusChanged": service}}); err != nil {
+				return
+			}
+		}
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeNext pushes a result of the subscription with the given ID.
+func writeNext(conn *websocket.Conn, id string, resp response) error {
+	payload, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	return conn.WriteJSON(subscriptionMessage{ID: id, Type: "next", Payload: payload})
+}
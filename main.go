@@ -33,7 +33,16 @@ func main() {
 		Debug:   debug,
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	ctx := context.Background()
+	shutdownTracing, err := provider.StartTracing(ctx, version)
+	if err != nil {
+		log.Printf("[WARN] OpenTelemetry tracing is disabled: %s", err)
+	}
+
+	err = providerserver.Serve(ctx, provider.New(version), opts)
+	if shutdownErr := shutdownTracing(ctx); shutdownErr != nil {
+		log.Printf("[WARN] unable to export OpenTelemetry spans: %s", shutdownErr)
+	}
 
 	if err != nil {
 		log.Fatal(err.Error())
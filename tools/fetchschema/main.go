@@ -0,0 +1,94 @@
+// Command fetchschema introspects the Timescale GraphQL API and writes its
+// schema in the GraphQL SDL. It authenticates with TIMESCALE_ACCESS_TOKEN, or
+// with the client credentials in TIMESCALE_ACCESS_KEY and
+// TIMESCALE_SECRET_KEY.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	tsClient "github.com/timescale/terraform-provider-timescale/client"
+	"github.com/timescale/terraform-provider-timescale/internal/graphqlgen"
+)
+
+const header = "# Code generated by fetchschema from the Timescale API. DO NOT EDIT.\n" +
+	"# Run `make schema` to update it, then `go generate ./client`.\n\n"
+
+func main() {
+	url := tsClient.DefaultURL
+	if value, ok := os.LookupEnv("TIMESCALE_DEV_URL"); ok {
+		url = value
+	}
+	var out string
+	flag.StringVar(&url, "url", url, "URL of the GraphQL API")
+	flag.StringVar(&out, "out", "schema.graphql", "path of the written schema")
+	flag.Parse()
+
+	if err := run(url, out); err != nil {
+		fmt.Fprintln(os.Stderr, "fetchschema:", err)
+		os.Exit(1)
+	}
+}
+
+func run(url, out string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	token := os.Getenv("TIMESCALE_ACCESS_TOKEN")
+	if token == "" {
+		var err error
+		token, err = exchangeCredentials(ctx, url, os.Getenv("TIMESCALE_ACCESS_KEY"), os.Getenv("TIMESCALE_SECRET_KEY"))
+		if err != nil {
+			return err
+		}
+	}
+	schema, err := graphqlgen.Introspect(ctx, url, http.Header{"Authorization": {"Bearer " + token}})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, []byte(header+schema), 0o644)
+}
+
+// exchangeCredentials exchanges client credentials for a JWT.
+func exchangeCredentials(ctx context.Context, url, accessKey, secretKey string) (string, error) {
+	if accessKey == "" || secretKey == "" {
+		return "", errors.New("set TIMESCALE_ACCESS_TOKEN, or TIMESCALE_ACCESS_KEY and TIMESCALE_SECRET_KEY")
+	}
+	body, err := json.Marshal(map[string]any{
+		"query":     `query GetJWTForClientCredentials($accessKey: String!, $secretKey: String!) { getJWTForClientCredentials(data: {accessKey: $accessKey, secretKey: $secretKey}) }`,
+		"variables": map[string]string{"accessKey": accessKey, "secretKey": secretKey},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var result struct {
+		Data struct {
+			GetJWTForClientCredentials string `json:"getJWTForClientCredentials"`
+		} `json:"data"`
+		Errors tsClient.Errors `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("exchanging client credentials: status %d: %w", resp.StatusCode, err)
+	}
+	if len(result.Errors) > 0 {
+		return "", fmt.Errorf("exchanging client credentials: %w", result.Errors)
+	}
+	return result.Data.GetJWTForClientCredentials, nil
+}
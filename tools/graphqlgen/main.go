@@ -0,0 +1,35 @@
+// Command graphqlgen generates the Go types and functions of the GraphQL
+// operations of a client. It is run by go generate.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/timescale/terraform-provider-timescale/internal/graphqlgen"
+)
+
+func main() {
+	var config graphqlgen.Config
+	var extensions, out string
+	flag.StringVar(&config.Package, "package", os.Getenv("GOPACKAGE"), "name of the generated package")
+	flag.StringVar(&config.Schema, "schema", "schema.graphql", "path of the schema")
+	flag.StringVar(&extensions, "extensions", "", "comma-separated paths of schema extensions")
+	flag.StringVar(&config.Operations, "operations", "queries", "directory of the operations")
+	flag.StringVar(&out, "out", "operations_gen.go", "path of the generated file")
+	flag.Parse()
+	if extensions != "" {
+		config.Extensions = strings.Split(extensions, ",")
+	}
+
+	src, err := graphqlgen.Generate(config)
+	if err == nil {
+		err = os.WriteFile(out, src, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "graphqlgen:", err)
+		os.Exit(1)
+	}
+}